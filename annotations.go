@@ -0,0 +1,268 @@
+package resource
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AnnotationFormats that can be converted to check run annotations.
+var AnnotationFormats = []string{"sarif", "checkstyle", "junit", "golangci-lint"}
+
+// ParseAnnotations converts a report in the given format into check run annotations.
+func ParseAnnotations(format string, content []byte) ([]Annotation, error) {
+	switch strings.ToLower(format) {
+	case "sarif":
+		return parseSARIF(content)
+	case "checkstyle":
+		return parseCheckstyle(content)
+	case "junit":
+		return parseJUnit(content)
+	case "golangci-lint":
+		return parseGolangciLint(content)
+	default:
+		return nil, fmt.Errorf("unknown annotations format: %s", format)
+	}
+}
+
+// parseSARIF parses a SARIF (v2.1.0) log.
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+func parseSARIF(content []byte) ([]Annotation, error) {
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				RuleID  string `json:"ruleId"`
+				Level   string `json:"level"`
+				Message struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+							EndLine   int `json:"endLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(content, &log); err != nil {
+		return nil, fmt.Errorf("failed to parse sarif: %s", err)
+	}
+
+	var annotations []Annotation
+	for _, run := range log.Runs {
+		for _, result := range run.Results {
+			if len(result.Locations) == 0 {
+				continue
+			}
+			location := result.Locations[0].PhysicalLocation
+			annotations = append(annotations, newAnnotation(
+				location.ArtifactLocation.URI,
+				location.Region.StartLine,
+				location.Region.EndLine,
+				sarifLevel(result.Level),
+				result.RuleID,
+				result.Message.Text,
+			))
+		}
+	}
+	return annotations, nil
+}
+
+func sarifLevel(level string) string {
+	switch level {
+	case "error":
+		return "failure"
+	case "note", "none":
+		return "notice"
+	default:
+		return "warning"
+	}
+}
+
+// parseCheckstyle parses a Checkstyle XML report.
+func parseCheckstyle(content []byte) ([]Annotation, error) {
+	var report struct {
+		Files []struct {
+			Name   string `xml:"name,attr"`
+			Errors []struct {
+				Line     int    `xml:"line,attr"`
+				Severity string `xml:"severity,attr"`
+				Message  string `xml:"message,attr"`
+				Source   string `xml:"source,attr"`
+			} `xml:"error"`
+		} `xml:"file"`
+	}
+	if err := xml.Unmarshal(content, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse checkstyle: %s", err)
+	}
+
+	var annotations []Annotation
+	for _, f := range report.Files {
+		for _, e := range f.Errors {
+			level := "warning"
+			switch e.Severity {
+			case "error":
+				level = "failure"
+			case "info", "ignore":
+				level = "notice"
+			}
+			annotations = append(annotations, newAnnotation(f.Name, e.Line, e.Line, level, e.Source, e.Message))
+		}
+	}
+	return annotations, nil
+}
+
+// junitSuite represents a (possibly nested) JUnit XML test suite.
+type junitSuite struct {
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []struct {
+		Name      string `xml:"name,attr"`
+		ClassName string `xml:"classname,attr"`
+		File      string `xml:"file,attr"`
+		Line      int    `xml:"line,attr"`
+		Failures  []struct {
+			Message string `xml:"message,attr"`
+			Text    string `xml:",chardata"`
+		} `xml:"failure"`
+		Errors []struct {
+			Message string `xml:"message,attr"`
+			Text    string `xml:",chardata"`
+		} `xml:"error"`
+	} `xml:"testcase"`
+}
+
+// parseJUnit parses a JUnit XML report. Only test cases with a file
+// attribute can be annotated.
+func parseJUnit(content []byte) ([]Annotation, error) {
+	var root junitSuite
+	if err := xml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse junit: %s", err)
+	}
+
+	var annotations []Annotation
+	var walk func(s junitSuite)
+	walk = func(s junitSuite) {
+		for _, c := range s.Cases {
+			if c.File == "" {
+				continue
+			}
+			title := c.Name
+			if c.ClassName != "" {
+				title = c.ClassName + "." + c.Name
+			}
+			for _, f := range append(c.Failures, c.Errors...) {
+				message := strings.TrimSpace(strings.Join([]string{f.Message, strings.TrimSpace(f.Text)}, "\n"))
+				annotations = append(annotations, newAnnotation(c.File, c.Line, c.Line, "failure", title, message))
+			}
+		}
+		for _, child := range s.Suites {
+			walk(child)
+		}
+	}
+	walk(root)
+	return annotations, nil
+}
+
+// parseGolangciLint parses the output of golangci-lint run --out-format json.
+func parseGolangciLint(content []byte) ([]Annotation, error) {
+	var report struct {
+		Issues []struct {
+			FromLinter string
+			Text       string
+			Severity   string
+			Pos        struct {
+				Filename string
+				Line     int
+			}
+			LineRange *struct {
+				From int
+				To   int
+			}
+		}
+	}
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse golangci-lint: %s", err)
+	}
+
+	var annotations []Annotation
+	for _, i := range report.Issues {
+		start, end := i.Pos.Line, i.Pos.Line
+		if i.LineRange != nil {
+			start, end = i.LineRange.From, i.LineRange.To
+		}
+		level := "warning"
+		if i.Severity == "error" {
+			level = "failure"
+		}
+		annotations = append(annotations, newAnnotation(i.Pos.Filename, start, end, level, i.FromLinter, i.Text))
+	}
+	return annotations, nil
+}
+
+// RelativeAnnotationPaths makes the paths of annotations relative to the repository at root,
+// since Github ignores annotations for other paths. Reports are usually generated in a task
+// where the repository is mounted elsewhere, so absolute paths outside of root are replaced by
+// their longest suffix that exists in the repository.
+func RelativeAnnotationPaths(root string, annotations []Annotation) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	for i := range annotations {
+		annotations[i].Path = relativePath(root, annotations[i].Path)
+	}
+	return nil
+}
+
+func relativePath(root, path string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	p := filepath.ToSlash(path)
+	for {
+		i := strings.Index(p, "/")
+		if i < 0 || i == len(p)-1 {
+			return path
+		}
+		p = p[i+1:]
+		if _, err := os.Stat(filepath.Join(root, p)); err == nil {
+			return p
+		}
+	}
+}
+
+// newAnnotation returns an annotation with a path relative to the repository
+// and a valid line range.
+func newAnnotation(path string, start, end int, level, title, message string) Annotation {
+	path = strings.TrimPrefix(path, "file://")
+	path = strings.TrimPrefix(path, "./")
+	if start < 1 {
+		start = 1
+	}
+	if end < start {
+		end = start
+	}
+	if message == "" {
+		message = title
+	}
+	return Annotation{
+		Path:      path,
+		StartLine: start,
+		EndLine:   end,
+		Level:     level,
+		Title:     title,
+		Message:   message,
+	}
+}
@@ -0,0 +1,146 @@
+package resource_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	tests := []struct {
+		description string
+		format      string
+		content     string
+		expected    []resource.Annotation
+	}{
+		{
+			description: "parses sarif",
+			format:      "sarif",
+			content: `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "gosec"}},
+    "results": [
+      {
+        "ruleId": "G104",
+        "level": "error",
+        "message": {"text": "Errors unhandled."},
+        "locations": [{"physicalLocation": {"artifactLocation": {"uri": "cmd/main.go"}, "region": {"startLine": 12, "endLine": 14}}}]
+      },
+      {
+        "ruleId": "G101",
+        "message": {"text": "Potential hardcoded credentials"},
+        "locations": [{"physicalLocation": {"artifactLocation": {"uri": "file://./config.go"}, "region": {"startLine": 3}}}]
+      },
+      {
+        "ruleId": "G000",
+        "message": {"text": "No location"}
+      }
+    ]
+  }]
+}`,
+			expected: []resource.Annotation{
+				{Path: "cmd/main.go", StartLine: 12, EndLine: 14, Level: "failure", Title: "G104", Message: "Errors unhandled."},
+				{Path: "config.go", StartLine: 3, EndLine: 3, Level: "warning", Title: "G101", Message: "Potential hardcoded credentials"},
+			},
+		},
+		{
+			description: "parses checkstyle",
+			format:      "checkstyle",
+			content: `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="5.0">
+  <file name="src/Main.java">
+    <error line="7" column="3" severity="error" message="Missing semicolon" source="syntax"/>
+    <error line="9" severity="info" message="Line is longer than 100 characters" source="LineLength"/>
+  </file>
+</checkstyle>`,
+			expected: []resource.Annotation{
+				{Path: "src/Main.java", StartLine: 7, EndLine: 7, Level: "failure", Title: "syntax", Message: "Missing semicolon"},
+				{Path: "src/Main.java", StartLine: 9, EndLine: 9, Level: "notice", Title: "LineLength", Message: "Line is longer than 100 characters"},
+			},
+		},
+		{
+			description: "parses junit",
+			format:      "junit",
+			content: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="resource">
+    <testcase name="TestCheck" classname="resource" file="check_test.go" line="42">
+      <failure message="not equal">expected 1, got 2</failure>
+    </testcase>
+    <testcase name="TestGet" classname="resource" file="in_test.go" line="10"/>
+    <testcase name="TestPut" classname="resource">
+      <failure message="no file"/>
+    </testcase>
+    <testsuite name="nested">
+      <testcase name="TestNested" file="nested_test.go">
+        <error message="panic"/>
+      </testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>`,
+			expected: []resource.Annotation{
+				{Path: "check_test.go", StartLine: 42, EndLine: 42, Level: "failure", Title: "resource.TestCheck", Message: "not equal\nexpected 1, got 2"},
+				{Path: "nested_test.go", StartLine: 1, EndLine: 1, Level: "failure", Title: "TestNested", Message: "panic"},
+			},
+		},
+		{
+			description: "parses golangci-lint",
+			format:      "golangci-lint",
+			content: `{
+  "Issues": [
+    {"FromLinter": "errcheck", "Text": "Error return value is not checked", "Severity": "", "Pos": {"Filename": "git.go", "Line": 95, "Column": 2}},
+    {"FromLinter": "gofmt", "Text": "File is not gofmt-ed", "Severity": "error", "Pos": {"Filename": "github.go", "Line": 20}, "LineRange": {"From": 20, "To": 22}}
+  ]
+}`,
+			expected: []resource.Annotation{
+				{Path: "git.go", StartLine: 95, EndLine: 95, Level: "warning", Title: "errcheck", Message: "Error return value is not checked"},
+				{Path: "github.go", StartLine: 20, EndLine: 22, Level: "failure", Title: "gofmt", Message: "File is not gofmt-ed"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			annotations, err := resource.ParseAnnotations(tc.format, []byte(tc.content))
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, annotations)
+			}
+		})
+	}
+}
+
+func TestParseAnnotationsErrors(t *testing.T) {
+	_, err := resource.ParseAnnotations("pmd", []byte(""))
+	assert.EqualError(t, err, "unknown annotations format: pmd")
+
+	_, err = resource.ParseAnnotations("sarif", []byte("<xml/>"))
+	assert.Error(t, err)
+}
+
+func TestRelativeAnnotationPaths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "annotations")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "cmd"), os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cmd", "main.go"), []byte("package main"), 0644))
+
+	annotations := []resource.Annotation{
+		{Path: "main.go"},
+		{Path: filepath.Join(dir, "cmd", "main.go")},
+		{Path: "/tmp/build/1a2b3c/pull-request/cmd/main.go"},
+		{Path: "/go/pkg/mod/example.com/lib/lib.go"},
+	}
+	require.NoError(t, resource.RelativeAnnotationPaths(dir, annotations))
+
+	var paths []string
+	for _, a := range annotations {
+		paths = append(paths, a.Path)
+	}
+	assert.Equal(t, []string{"main.go", "cmd/main.go", "cmd/main.go", "/go/pkg/mod/example.com/lib/lib.go"}, paths)
+}
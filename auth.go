@@ -0,0 +1,149 @@
+package resource
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const defaultV3Endpoint = "https://api.github.com/"
+
+// NewTokenSource returns the token source used to authenticate against the
+// Github APIs and when fetching over git. If a Github App is configured the
+// tokens are installation tokens, which are minted (and refreshed when they
+// expire) using the given HTTP client, or the client used for the APIs if it
+// is nil. Pass the same token source to the clients used in a single run, so
+// they share the installation token.
+func NewTokenSource(s *Source, client *http.Client) (oauth2.TokenSource, error) {
+	if client == nil {
+		client = newHTTPClient(s)
+	}
+	if s.GithubAppID == 0 {
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: s.AccessToken}), nil
+	}
+
+	key, err := parsePrivateKey(s.GithubAppPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse github app private key: %s", err)
+	}
+
+	endpoint := s.V3Endpoint
+	if endpoint == "" {
+		endpoint = defaultV3Endpoint
+	}
+
+	return oauth2.ReuseTokenSource(nil, &appTokenSource{
+		client:         client,
+		endpoint:       strings.TrimSuffix(endpoint, "/"),
+		appID:          s.GithubAppID,
+		installationID: s.GithubAppInstallationID,
+		key:            key,
+	}), nil
+}
+
+// appTokenSource mints installation tokens for a Github App.
+// https://developer.github.com/apps/building-github-apps/authenticating-with-github-apps/
+type appTokenSource struct {
+	client         *http.Client
+	endpoint       string
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+}
+
+// Token creates a new installation access token.
+func (a *appTokenSource) Token() (*oauth2.Token, error) {
+	jwt, err := a.jwt(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create jwt: %s", err)
+	}
+
+	u := fmt.Sprintf("%s/app/installations/%d/access_tokens", a.endpoint, a.installationID)
+	req, err := http.NewRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create installation token: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to create installation token: unexpected status: %s", resp.Status)
+	}
+
+	var token struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode installation token: %s", err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.Token,
+		Expiry:      token.ExpiresAt,
+	}, nil
+}
+
+// jwt returns a JSON Web Token (RS256) identifying the Github App. The issued
+// at time is backdated to allow for clock drift, and Github rejects tokens
+// that expire more than 10 minutes into the future.
+func (a *appTokenSource) jwt(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]int64{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": a.appID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey parses a PEM encoded RSA key in either PKCS1 or PKCS8 form.
+func parsePrivateKey(s string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("no pem data found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an rsa private key")
+	}
+	return rsaKey, nil
+}
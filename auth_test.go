@@ -0,0 +1,246 @@
+package resource_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestGithubAppAuthentication(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	tests := []struct {
+		description string
+		expiresIn   time.Duration
+		calls       int
+		expected    []string
+	}{
+		{
+			description: "installation tokens are reused until they expire",
+			expiresIn:   time.Hour,
+			calls:       3,
+			expected:    []string{"token1", "token1", "token1"},
+		},
+		{
+			description: "installation tokens are refreshed when they expire",
+			expiresIn:   time.Second,
+			calls:       3,
+			expected:    []string{"token1", "token2", "token3"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var minted int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/app/installations/2/access_tokens" {
+					http.NotFound(w, r)
+					return
+				}
+				if err := verifyTestJWT(&key.PublicKey, r.Header.Get("Authorization"), 1); err != nil {
+					http.Error(w, err.Error(), http.StatusUnauthorized)
+					return
+				}
+				n := atomic.AddInt32(&minted, 1)
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprintf(w, `{"token":"token%d","expires_at":"%s"}`, n, time.Now().Add(tc.expiresIn).Format(time.RFC3339))
+			}))
+			defer server.Close()
+
+			source := resource.Source{
+				Repository:              "itsdalmo/test-repository",
+				V3Endpoint:              server.URL + "/",
+				V4Endpoint:              server.URL + "/graphql",
+				GithubAppID:             1,
+				GithubAppInstallationID: 2,
+				GithubAppPrivateKey:     privateKey,
+			}
+			require.NoError(t, source.Validate())
+
+			tokenSource, err := resource.NewTokenSource(&source, server.Client())
+			require.NoError(t, err)
+
+			var tokens []string
+			for i := 0; i < tc.calls; i++ {
+				token, err := tokenSource.Token()
+				require.NoError(t, err)
+				tokens = append(tokens, token.AccessToken)
+			}
+			assert.Equal(t, tc.expected, tokens)
+
+			// The git client bakes the installation token into the endpoint.
+			git, err := resource.NewGitClient(&source, nil, "", ioutil.Discard)
+			require.NoError(t, err)
+
+			endpoint, err := git.Endpoint("https://github.com/itsdalmo/test-repository")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(endpoint, "https://x-access-token:token"), endpoint)
+		})
+	}
+}
+
+func TestGithubAppTokenIsSharedBetweenClients(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	var minted int32
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/app/installations/2/access_tokens" {
+			n := atomic.AddInt32(&minted, 1)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token":"token%d","expires_at":"%s"}`, n, time.Now().Add(time.Hour).Format(time.RFC3339))
+			return
+		}
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:              "itsdalmo/test-repository",
+		V3Endpoint:              server.URL + "/",
+		V4Endpoint:              server.URL + "/graphql",
+		GithubAppID:             1,
+		GithubAppInstallationID: 2,
+		GithubAppPrivateKey:     privateKey,
+	}
+	require.NoError(t, source.Validate())
+
+	tokenSource, err := resource.NewTokenSource(&source, server.Client())
+	require.NoError(t, err)
+
+	git, err := resource.NewGitClient(&source, tokenSource, "", ioutil.Discard)
+	require.NoError(t, err)
+	endpoint, err := git.Endpoint("https://github.com/itsdalmo/test-repository")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(endpoint, "https://x-access-token:token1@"), endpoint)
+
+	client, err := resource.NewGithubClient(&source, tokenSource)
+	require.NoError(t, err)
+	_, err = client.ListModifiedFiles(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token1", authorization)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&minted))
+}
+
+func TestGithubAppAuthenticationFailure(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:              "itsdalmo/test-repository",
+		V3Endpoint:              server.URL,
+		V4Endpoint:              server.URL + "/graphql",
+		GithubAppID:             1,
+		GithubAppInstallationID: 2,
+		GithubAppPrivateKey:     privateKey,
+	}
+
+	tokenSource, err := resource.NewTokenSource(&source, server.Client())
+	require.NoError(t, err)
+
+	_, err = tokenSource.Token()
+	assert.EqualError(t, err, "failed to create installation token: unexpected status: 401 Unauthorized")
+}
+
+func TestSourceValidateAuthentication(t *testing.T) {
+	tests := []struct {
+		description string
+		source      resource.Source
+		expected    string
+	}{
+		{
+			description: "access token is valid",
+			source:      resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+		},
+		{
+			description: "github app is valid",
+			source:      resource.Source{Repository: "itsdalmo/test-repository", GithubAppID: 1, GithubAppInstallationID: 2, GithubAppPrivateKey: "key"},
+		},
+		{
+			description: "requires credentials",
+			source:      resource.Source{Repository: "itsdalmo/test-repository"},
+			expected:    "access_token (or github_app_id) must be set",
+		},
+		{
+			description: "does not allow both access token and github app",
+			source:      resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", GithubAppID: 1, GithubAppInstallationID: 2, GithubAppPrivateKey: "key"},
+			expected:    "access_token cannot be set together with github_app_id",
+		},
+		{
+			description: "requires the full github app configuration",
+			source:      resource.Source{Repository: "itsdalmo/test-repository", GithubAppID: 1},
+			expected:    "github_app_id, github_app_installation_id and github_app_private_key must be set together",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			err := tc.source.Validate()
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
+		})
+	}
+}
+
+// verifyTestJWT checks the signature and issuer of a bearer JWT.
+func verifyTestJWT(key *rsa.PublicKey, header string, issuer int64) error {
+	parts := strings.Split(strings.TrimPrefix(header, "Bearer "), ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed jwt: %s", header)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature); err != nil {
+		return err
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	var claims struct {
+		Iss int64 `json:"iss"`
+	}
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return err
+	}
+	if claims.Iss != issuer {
+		return fmt.Errorf("unexpected issuer: %d", claims.Iss)
+	}
+	return nil
+}
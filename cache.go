@@ -0,0 +1,183 @@
+package resource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultCacheMaxAge is how long cached responses are kept after they were last used.
+const defaultCacheMaxAge = 7 * 24 * time.Hour
+
+// defaultCacheDir returns the directory used for the HTTP cache when none is configured, which
+// is a directory per resource (i.e. repository and endpoint) in the temporary directory.
+func defaultCacheDir(s *Source) string {
+	h := sha256.New()
+	for _, v := range []string{s.ProviderName(), s.Endpoint, s.V3Endpoint, s.Repository} {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return filepath.Join(os.TempDir(), "github-pr-resource-cache", hex.EncodeToString(h.Sum(nil))[:16])
+}
+
+// pruneCache removes the cached responses in the directory that have not been used for longer
+// than the max age, so the cache does not keep growing.
+func pruneCache(dir string, maxAge time.Duration) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		if !f.IsDir() && time.Since(f.ModTime()) > maxAge {
+			os.Remove(filepath.Join(dir, f.Name()))
+		}
+	}
+}
+
+// CacheTransport makes conditional GET requests using the ETag and Last-Modified headers of
+// responses cached in a directory, and returns the cached response when the server responds
+// with 304 (Not Modified). Github does not count such responses against the rate limit.
+//
+// The cache is best effort: responses that cannot be read from or written to the
+// directory are simply requested again.
+type CacheTransport struct {
+	// Base is the transport used to make requests (defaults to http.DefaultTransport).
+	Base http.RoundTripper
+	// Dir is the directory the responses are cached in.
+	Dir string
+}
+
+// cachedResponse is a response stored on disk.
+type cachedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.base().RoundTrip(req)
+	}
+
+	path := t.path(req)
+	cached := readCachedResponse(path)
+
+	r := req
+	if cached != nil {
+		r = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			r.Header.Set("If-None-Match", etag)
+		}
+		if modified := cached.Header.Get("Last-Modified"); modified != "" {
+			r.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := t.base().RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+
+		// Mark the cached response as used, so it is not pruned.
+		now := time.Now()
+		os.Chtimes(path, now, now)
+
+		// Use the headers of the new response (e.g. the rate limit) where they are set.
+		header := cached.Header.Clone()
+		for k, v := range resp.Header {
+			header[k] = v
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", cached.StatusCode, http.StatusText(cached.StatusCode)),
+			StatusCode:    cached.StatusCode,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(cached.Body)),
+			ContentLength: int64(len(cached.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	if resp.StatusCode == http.StatusOK && (resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "") {
+		body, err := peekBody(resp)
+		if err != nil {
+			return nil, err
+		}
+		writeCachedResponse(path, &cachedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       body,
+		})
+	}
+	return resp, nil
+}
+
+// path returns the path of the cached response for a request. The credentials are not part
+// of the key, since the tokens of Github Apps change for every check and the directory is
+// already per resource. Cached responses are only returned when the server has responded
+// with 304 (Not Modified) to the request with the current credentials.
+func (t *CacheTransport) path(req *http.Request) string {
+	h := sha256.New()
+	h.Write([]byte(req.URL.String()))
+	h.Write([]byte{0})
+	h.Write([]byte(req.Header.Get("Accept")))
+	return filepath.Join(t.Dir, hex.EncodeToString(h.Sum(nil))+".json")
+}
+
+func (t *CacheTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func readCachedResponse(path string) *cachedResponse {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(b, &cached); err != nil {
+		return nil
+	}
+	return &cached
+}
+
+// writeCachedResponse writes the response to a temporary file which is renamed, so
+// concurrent requests never read a partially written response.
+func writeCachedResponse(path string, cached *cachedResponse) {
+	b, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
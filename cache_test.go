@@ -0,0 +1,172 @@
+package resource_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestCacheTransport(t *testing.T) {
+	tests := []struct {
+		description     string
+		method          string
+		header          map[string]string
+		expectedCalls   int
+		expectedHeaders []string
+	}{
+		{
+			description:     "uses the etag of cached responses",
+			method:          http.MethodGet,
+			header:          map[string]string{"ETag": `"abc"`},
+			expectedCalls:   2,
+			expectedHeaders: []string{"", `If-None-Match: "abc"`},
+		},
+		{
+			description:     "uses the last modified time of cached responses",
+			method:          http.MethodGet,
+			header:          map[string]string{"Last-Modified": "Mon, 02 Jan 2006 15:04:05 GMT"},
+			expectedCalls:   2,
+			expectedHeaders: []string{"", "If-Modified-Since: Mon, 02 Jan 2006 15:04:05 GMT"},
+		},
+		{
+			description:     "does not cache responses without validators",
+			method:          http.MethodGet,
+			expectedCalls:   2,
+			expectedHeaders: []string{"", ""},
+		},
+		{
+			description:     "does not cache other methods",
+			method:          http.MethodPost,
+			header:          map[string]string{"ETag": `"abc"`},
+			expectedCalls:   2,
+			expectedHeaders: []string{"", ""},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var headers []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var h string
+				if v := r.Header.Get("If-None-Match"); v != "" {
+					h = "If-None-Match: " + v
+				}
+				if v := r.Header.Get("If-Modified-Since"); v != "" {
+					h = "If-Modified-Since: " + v
+				}
+				headers = append(headers, h)
+
+				for k, v := range tc.header {
+					w.Header().Set(k, v)
+				}
+				if h != "" {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				fmt.Fprintf(w, "response %d", len(headers))
+			}))
+			defer server.Close()
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			client := &http.Client{Transport: &resource.CacheTransport{Dir: dir}}
+			for i := 0; i < 2; i++ {
+				req, err := http.NewRequest(tc.method, server.URL+"/repos/itsdalmo/test-repository/pulls/1/files", nil)
+				require.NoError(t, err)
+				// The cache is shared between tokens, e.g. the installation tokens of a Github App.
+				req.Header.Set("Authorization", fmt.Sprintf("Bearer installation-token-%d", i))
+
+				resp, err := client.Do(req)
+				require.NoError(t, err)
+				body, err := ioutil.ReadAll(resp.Body)
+				resp.Body.Close()
+				require.NoError(t, err)
+
+				// Cached responses are returned as the original response.
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				if tc.expectedHeaders[i] != "" {
+					assert.Equal(t, "response 1", string(body))
+				} else {
+					assert.Equal(t, fmt.Sprintf("response %d", i+1), string(body))
+				}
+			}
+			assert.Equal(t, tc.expectedCalls, len(headers))
+			assert.Equal(t, tc.expectedHeaders, headers)
+		})
+	}
+}
+
+func TestGithubClientCache(t *testing.T) {
+	var calls, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("ETag", `"files"`)
+		if r.Header.Get("If-None-Match") == `"files"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprint(w, `[{"filename":"README.md"}]`)
+	}))
+	defer server.Close()
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	source := resource.Source{
+		Repository:   "itsdalmo/test-repository",
+		AccessToken:  "oauthtoken",
+		V3Endpoint:   server.URL + "/",
+		V4Endpoint:   server.URL + "/graphql",
+		HTTPCacheDir: dir,
+	}
+	require.NoError(t, source.Validate())
+
+	// The cache is shared between clients, e.g. for subsequent checks.
+	for i := 0; i < 2; i++ {
+		client, err := resource.NewGithubClient(&source, nil)
+		require.NoError(t, err)
+
+		files, err := client.ListModifiedFiles(1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"README.md"}, files)
+		}
+	}
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 1, notModified)
+}
+
+func TestGithubClientCachePrunesUnusedResponses(t *testing.T) {
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	unused, used := filepath.Join(dir, "unused.json"), filepath.Join(dir, "used.json")
+	for _, f := range []string{unused, used} {
+		require.NoError(t, ioutil.WriteFile(f, []byte("{}"), 0600))
+	}
+	old := time.Now().AddDate(0, 0, -8)
+	require.NoError(t, os.Chtimes(unused, old, old))
+
+	source := resource.Source{
+		Repository:   "itsdalmo/test-repository",
+		AccessToken:  "oauthtoken",
+		HTTPCacheDir: dir,
+	}
+	_, err := resource.NewGithubClient(&source, nil)
+	require.NoError(t, err)
+
+	_, err = os.Stat(unused)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(used)
+	assert.NoError(t, err)
+}
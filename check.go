@@ -2,14 +2,19 @@ package resource
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Check (business logic)
-func Check(request CheckRequest, manager Github) (CheckResponse, error) {
+func Check(request CheckRequest, manager Provider) (CheckResponse, error) {
 	var response CheckResponse
 
 	pulls, err := manager.ListOpenPullRequests()
@@ -19,97 +24,334 @@ func Check(request CheckRequest, manager Github) (CheckResponse, error) {
 
 	disableSkipCI := request.Source.DisableCISkip
 
-Loop:
-	for _, p := range pulls {
+	// Resolve the members of the allowed teams once per check.
+	restrictAuthors := len(request.Source.AllowedAuthors) > 0 || len(request.Source.AllowedTeams) > 0
+	allowedAuthors := append([]string(nil), request.Source.AllowedAuthors...)
+	for _, t := range request.Source.AllowedTeams {
+		org, team, err := parseTeam(t)
+		if err != nil {
+			return nil, err
+		}
+		members, err := manager.ListTeamMembers(org, team)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list members of team %s: %s", t, err)
+		}
+		allowedAuthors = append(allowedAuthors, members...)
+	}
+
+	var triggerPhrase *regexp.Regexp
+	if request.Source.TriggerPhrase != "" {
+		triggerPhrase, err = regexp.Compile(request.Source.TriggerPhrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile trigger phrase: %s", err)
+		}
+	}
+
+	matchAll := strings.EqualFold(request.Source.LabelsMatch, "all")
+	matchLabels := func(labels []string) bool {
+		return MatchLabels(labels, request.Source.Labels, request.Source.IgnoreLabels, matchAll)
+	}
+	filterLabels := len(request.Source.Labels) > 0 || len(request.Source.IgnoreLabels) > 0
+
+	latestPush := strings.EqualFold(request.Source.PathsScope, "latest_push")
+
+	// checkPullRequest returns the new versions of a pull request, or a rebuild if it is only
+	// new because the base branch has moved.
+	checkPullRequest := func(p *PullRequest) ([]Version, *baseRebuild, error) {
+		var versions []Version
+		var err error
+
+		// previousCommit returns the tip of the pull request at the time of the previous version,
+		// or an empty string if there is none (e.g. the pull request has been opened since).
+		var previous *string
+		previousCommit := func() (string, error) {
+			if previous != nil {
+				return *previous, nil
+			}
+			sha := request.Version.Commit
+			if request.Version.PR != strconv.Itoa(p.Number) {
+				sha, err = manager.GetPullRequestCommitBefore(p.Number, request.Version.CommittedDate)
+				if err != nil {
+					return "", fmt.Errorf("failed to get previous commit: %s", err)
+				}
+			}
+			previous = &sha
+			return sha, nil
+		}
+
 		// [ci skip]/[skip ci] in Pull request title
 		if !disableSkipCI && ContainsSkipCI(p.Title) {
-			continue
+			return nil, nil, nil
 		}
 		// [ci skip]/[skip ci] in Commit message
 		if !disableSkipCI && ContainsSkipCI(p.Tip.Message) {
-			continue
+			return nil, nil, nil
 		}
 		// Filter pull request if the BaseBranch does not match the one specified in source
 		if request.Source.BaseBranch != "" && p.PullRequestObject.BaseRefName != request.Source.BaseBranch {
-			continue
+			return nil, nil, nil
 		}
-		// Filter out commits that are too old.
-		if !p.Tip.CommittedDate.Time.After(request.Version.CommittedDate) {
-			continue
+		// Filter out drafts.
+		if request.Source.IgnoreDrafts && p.IsDraft {
+			return nil, nil, nil
+		}
+		// Filter out pull requests with merge conflicts (unless Github is still computing it).
+		if request.Source.IgnoreConflicting && p.Mergeable == MergeableStateConflicting {
+			return nil, nil, nil
+		}
+		// Filter out pull requests that are not in a merge group of the merge queue.
+		if request.Source.MergeQueue && p.MergeGroup.OID == "" {
+			return nil, nil, nil
+		}
+		// Filter out pull requests that do not have at least one (or all) of the desired labels,
+		// or that have one of the ignored labels.
+		var labels []string
+		for _, l := range p.Labels {
+			if l.Name != "" {
+				labels = append(labels, l.Name)
+			}
+		}
+		if !matchLabels(labels) {
+			return nil, nil, nil
+		}
+		// Filter out pull requests where the required statuses of the tip have not passed (yet).
+		passed, passedAt, err := RequiredStatusesPassed(p.Statuses, request.Source.RequiredStatuses)
+		if err != nil {
+			return nil, nil, fmt.Errorf("required status match failed: %s", err)
+		}
+		if !passed {
+			return nil, nil, nil
 		}
 
-		// Filter out pull request if it does not contain at least one of the desired labels
-		if len(request.Source.Labels) > 0 {
-			labelFound := false
+		// Date the version by when the tip was pushed if that happened after it was committed,
+		// so force pushes and rebases that keep the original dates produce a new version.
+		version := NewVersion(p)
+		if p.PushedTime.After(version.CommittedDate) {
+			version.CommittedDate = p.PushedTime
+		}
 
-		LabelLoop:
-			for _, wantedLabel := range request.Source.Labels {
-				for _, targetLabel := range p.Labels {
-					if targetLabel.Name == wantedLabel {
-						labelFound = true
-						break LabelLoop
-					}
+		// Date the version by when the pull request was updated if the tip has changed since the
+		// previous version without being dated after it (e.g. an old commit that was cherry-picked
+		// or pushed late), so that every new tip produces a new version. The pushes to the pull
+		// request tell whether it has changed when they are all listed, so the tip at the time of
+		// the previous version is only looked up for other updates (e.g. comments) if they are not.
+		if request.Version.PR != "" && !version.CommittedDate.After(request.Version.CommittedDate) && p.UpdatedAt.After(request.Version.CommittedDate) {
+			changed := p.PushedAfter.After(request.Version.CommittedDate)
+			if !changed && (!p.PushesListed || request.Version.PR == strconv.Itoa(p.Number)) {
+				sha, err := previousCommit()
+				if err != nil {
+					return nil, nil, err
+				}
+				changed = sha != p.Tip.OID
+			}
+			if changed {
+				version.CommittedDate = p.UpdatedAt
+			}
+		}
+
+		// Date the version by when the pull request was marked as ready for review if that
+		// happened after the last commit, so drafts trigger once they are ready.
+		if request.Source.IgnoreDrafts && p.ReadyForReviewTime.After(version.CommittedDate) {
+			version.CommittedDate = p.ReadyForReviewTime
+		}
+
+		// Date the version by when the last of the required statuses passed if that happened after
+		// the last commit, so pull requests trigger once the statuses pass.
+		if passedAt.After(version.CommittedDate) {
+			version.CommittedDate = passedAt
+		}
+
+		// Date the version by when the labels changed to match if that happened after the last
+		// commit, so labelling a pull request (or removing an ignored label) triggers a new version.
+		if filterLabels {
+			if matchedAt := LabelsMatchedAt(labels, p.LabelEvents, matchLabels); matchedAt.After(version.CommittedDate) {
+				version.CommittedDate = matchedAt
+			}
+		}
+
+		// Versions in the merge queue are for the merge group commit, which is recreated
+		// (with a new date) whenever the pull requests ahead of it in the queue change.
+		if request.Source.MergeQueue {
+			version.MergeGroup = p.MergeGroup.OID
+			if p.MergeGroup.CommittedDate.Time.After(version.CommittedDate) {
+				version.CommittedDate = p.MergeGroup.CommittedDate.Time
+			}
+		}
+
+		// Date the version by the latest authorised comment matching the trigger phrase if
+		// it was made after the last commit, so builds can be retriggered without a push.
+		if triggerPhrase != nil {
+			for _, c := range p.Comments {
+				if !c.CreatedAt.After(version.CommittedDate) || !triggerPhrase.MatchString(c.Body) {
+					continue
+				}
+				if !IsAuthorisedComment(c, allowedAuthors, request.Source.IgnoredAuthors) {
+					continue
 				}
+				version.CommittedDate = c.CreatedAt
+				version.Comment = strconv.FormatInt(c.DatabaseId, 10)
 			}
+		}
 
-			if !labelFound {
-				continue Loop
+		// Date the version by the tip of the base branch if it was committed after the pull
+		// request was last updated, so the pull request is tested against the new base. The
+		// number of pull requests that are rebuilt this way is limited below.
+		var rebuild *baseRebuild
+		if request.Source.TrackBaseBranch {
+			version.BaseCommit = p.BaseTip.OID
+			if p.BaseTip.CommittedDate.Time.After(version.CommittedDate) {
+				if !version.CommittedDate.After(request.Version.CommittedDate) {
+					rebuild = &baseRebuild{updated: version.CommittedDate}
+				}
+				version.CommittedDate = p.BaseTip.CommittedDate.Time
 			}
 		}
 
+		// Filter out commits that are too old.
+		if !version.CommittedDate.After(request.Version.CommittedDate) {
+			return nil, nil, nil
+		}
+
 		// Filter out forks.
 		if request.Source.DisableForks && p.IsCrossRepository {
-			continue
+			return nil, nil, nil
+		}
+
+		// Filter on the author of the pull request (or the commit, if the author is unknown).
+		author := p.Author.Login
+		if author == "" {
+			author = p.Tip.Author.User.Login
+		}
+		if ContainsLogin(request.Source.IgnoredAuthors, author) {
+			return nil, nil, nil
+		}
+		if restrictAuthors && !ContainsLogin(allowedAuthors, author) {
+			return nil, nil, nil
 		}
 
 		// Filter pull request if it does not have the required number of approved review(s).
 		if p.ApprovedReviewCount < request.Source.RequiredReviewApprovals {
-			continue
+			return nil, nil, nil
 		}
 
 		// Fetch files once if paths/ignore_paths are specified.
 		var files []string
 
 		if len(request.Source.Paths) > 0 || len(request.Source.IgnorePaths) > 0 {
-			files, err = manager.ListModifiedFiles(p.Number)
-			if err != nil {
-				return nil, fmt.Errorf("failed to list modified files: %s", err)
+			// Only use the files changed since the previous version when the scope is the latest
+			// push. If there is no previous commit in the pull request, or the tip has not moved
+			// (e.g. the version was triggered by a comment), the whole pull request is used.
+			var base string
+			if latestPush && request.Version.PR != "" {
+				if base, err = previousCommit(); err != nil {
+					return nil, nil, err
+				}
+			}
+
+			if base != "" && base != p.Tip.OID {
+				files, err = manager.ListModifiedFilesBetween(base, p.Tip.OID)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to compare commits: %s", err)
+				}
+			} else if p.Files != nil {
+				files = p.Files
+			} else {
+				files, err = manager.ListModifiedFiles(p.Number)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to list modified files: %s", err)
+				}
 			}
 		}
 
 		// Skip version if no files match the specified paths.
 		if len(request.Source.Paths) > 0 {
-			var wanted []string
-			for _, pattern := range request.Source.Paths {
-				w, err := FilterPath(files, pattern)
-				if err != nil {
-					return nil, fmt.Errorf("path match failed: %s", err)
-				}
-				wanted = append(wanted, w...)
+			wanted, err := FilterPaths(files, request.Source.Paths)
+			if err != nil {
+				return nil, nil, fmt.Errorf("path match failed: %s", err)
 			}
 			if len(wanted) == 0 {
-				continue Loop
+				return nil, nil, nil
 			}
 		}
 
 		// Skip version if all files are ignored.
 		if len(request.Source.IgnorePaths) > 0 {
-			wanted := files
-			for _, pattern := range request.Source.IgnorePaths {
-				wanted, err = FilterIgnorePath(wanted, pattern)
-				if err != nil {
-					return nil, fmt.Errorf("ignore path match failed: %s", err)
-				}
+			wanted, err := FilterIgnorePaths(files, request.Source.IgnorePaths)
+			if err != nil {
+				return nil, nil, fmt.Errorf("ignore path match failed: %s", err)
 			}
 			if len(wanted) == 0 {
-				continue Loop
+				return nil, nil, nil
+			}
+		}
+
+		if rebuild != nil {
+			rebuild.version = version
+			return nil, rebuild, nil
+		}
+
+		// Add a version for each of the earlier commits since the previous version of the pull
+		// request, walking back from the tip since commits can be pushed long after they were
+		// made. They are dated like the tip so they are kept in order right before it. There is
+		// no need to list them if there is no previous version, since only the latest is returned.
+		if request.Source.EveryCommit && request.Version.PR != "" {
+			base, err := previousCommit()
+			if err != nil {
+				return nil, nil, err
 			}
+			if base != p.Tip.OID {
+				commits, err := manager.ListPullRequestCommits(p.Number, base)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to list commits: %s", err)
+				}
+				for _, c := range commits {
+					if c.OID == version.Commit || (!disableSkipCI && ContainsSkipCI(c.Message)) {
+						continue
+					}
+					v := version
+					v.Commit = c.OID
+					versions = append(versions, v)
+				}
+			}
+		}
+		return append(versions, version), nil, nil
+	}
+
+	// Check the pull requests concurrently if specified, keeping the versions in the order of
+	// the pull requests so the response is the same regardless of the concurrency.
+	results := make([][]Version, len(pulls))
+	rebuildResults := make([]*baseRebuild, len(pulls))
+	errs := runConcurrently(len(pulls), request.Source.CheckConcurrency, func(i int) error {
+		var err error
+		results[i], rebuildResults[i], err = checkPullRequest(pulls[i])
+		return err
+	})
+	if err := newCheckError(pulls, errs); err != nil {
+		return nil, err
+	}
+
+	var rebuilds []baseRebuild
+	for i := range pulls {
+		response = append(response, results[i]...)
+		if rebuildResults[i] != nil {
+			rebuilds = append(rebuilds, *rebuildResults[i])
 		}
-		response = append(response, NewVersion(p))
+	}
+
+	// Rebuild the most recently updated pull requests when the base branch has moved, with
+	// the most recently updated pull request as the latest version.
+	sort.SliceStable(rebuilds, func(i, j int) bool {
+		return rebuilds[i].updated.After(rebuilds[j].updated)
+	})
+	if max := request.Source.MaxBaseBranchRebuilds; max > 0 && len(rebuilds) > max {
+		rebuilds = rebuilds[:max]
+	}
+	for i := len(rebuilds) - 1; i >= 0; i-- {
+		response = append(response, rebuilds[i].version)
 	}
 
 	// Sort the commits by date
-	sort.Sort(response)
+	sort.Stable(response)
 
 	// If there are no new but an old version = return the old
 	if len(response) == 0 && request.Version.PR != "" {
@@ -122,42 +364,285 @@ Loop:
 	return response, nil
 }
 
+// runConcurrently calls fn for each index up to n using at most concurrency goroutines, and
+// returns the errors by index. No further calls are made once one of them has failed.
+func runConcurrently(n, concurrency int, fn func(int) error) []error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	errs := make([]error, n)
+	jobs := make(chan int)
+
+	var failed int32
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if atomic.LoadInt32(&failed) != 0 {
+					continue
+				}
+				if err := fn(i); err != nil {
+					errs[i] = err
+					atomic.StoreInt32(&failed, 1)
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return errs
+}
+
+// CheckErrors is returned when checking more than one pull request failed.
+type CheckErrors []string
+
+func (e CheckErrors) Error() string {
+	return fmt.Sprintf("failed to check %d pull requests: %s", len(e), strings.Join(e, "; "))
+}
+
+// newCheckError returns the only error, or the errors of each pull request if there are several.
+func newCheckError(pulls []*PullRequest, errs []error) error {
+	var first error
+	var messages CheckErrors
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if first == nil {
+			first = err
+		}
+		messages = append(messages, fmt.Sprintf("#%d: %s", pulls[i].Number, err))
+	}
+	if len(messages) > 1 {
+		return messages
+	}
+	return first
+}
+
+// baseRebuild is a version that is only new because the base branch has moved.
+type baseRebuild struct {
+	version Version
+	updated time.Time
+}
+
 // ContainsSkipCI returns true if a string contains [ci skip] or [skip ci].
 func ContainsSkipCI(s string) bool {
 	re := regexp.MustCompile("(?i)\\[(ci skip|skip ci)\\]")
 	return re.MatchString(s)
 }
 
-// FilterIgnorePath ...
-func FilterIgnorePath(files []string, pattern string) ([]string, error) {
+// IsAuthorisedComment returns true if the comment was made by an owner, member or
+// collaborator of the repository, or by one of the allowed authors.
+func IsAuthorisedComment(c CommentObject, allowedAuthors, ignoredAuthors []string) bool {
+	if ContainsLogin(ignoredAuthors, c.Author.Login) {
+		return false
+	}
+	switch c.AuthorAssociation {
+	case CommentAuthorAssociationOwner, CommentAuthorAssociationMember, CommentAuthorAssociationCollaborator:
+		return true
+	}
+	return ContainsLogin(allowedAuthors, c.Author.Login)
+}
+
+// RequiredStatusesPassed returns true if each of the required statuses matches at least one
+// status, and all matching statuses are in the required state (success, if not set). It also
+// returns the time the last of the matching statuses was updated.
+func RequiredStatusesPassed(statuses []Status, required []RequiredStatus) (bool, time.Time, error) {
+	var updatedAt time.Time
+	for _, r := range required {
+		state := r.State
+		if state == "" {
+			state = "success"
+		}
+		found := false
+		for _, s := range statuses {
+			match, err := r.Match(s.Context)
+			if err != nil {
+				return false, time.Time{}, err
+			}
+			if !match {
+				continue
+			}
+			if !strings.EqualFold(s.State, state) {
+				return false, time.Time{}, nil
+			}
+			if s.UpdatedAt.After(updatedAt) {
+				updatedAt = s.UpdatedAt
+			}
+			found = true
+		}
+		if !found {
+			return false, time.Time{}, nil
+		}
+	}
+	return true, updatedAt, nil
+}
+
+// MatchLabels returns true if the labels contain at least one (or all, if matchAll is set)
+// of the wanted labels, and none of the ignored labels.
+func MatchLabels(labels, wanted, ignored []string, matchAll bool) bool {
+	for _, l := range ignored {
+		if containsLabel(labels, l) {
+			return false
+		}
+	}
+	if len(wanted) == 0 {
+		return true
+	}
+	for _, l := range wanted {
+		found := containsLabel(labels, l)
+		if found && !matchAll {
+			return true
+		}
+		if !found && matchAll {
+			return false
+		}
+	}
+	return matchAll
+}
+
+// LabelsMatchedAt returns the time of the last label event that made the labels match, by
+// undoing the events (oldest first) from the current labels. Returns the zero time if the
+// labels matched before the first event.
+func LabelsMatchedAt(labels []string, events []LabelEvent, match func([]string) bool) time.Time {
+	current := append([]string(nil), labels...)
+	for i := len(events) - 1; i >= 0; i-- {
+		after := match(current)
+
+		e := events[i]
+		if e.Added {
+			current = removeLabel(current, e.Name)
+		} else if !containsLabel(current, e.Name) {
+			current = append(current, e.Name)
+		}
+
+		if after && !match(current) {
+			return e.CreatedAt
+		}
+	}
+	return time.Time{}
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func removeLabel(labels []string, label string) []string {
 	var out []string
-	for _, file := range files {
-		match, err := filepath.Match(pattern, file)
-		if err != nil {
-			return nil, err
+	for _, l := range labels {
+		if l != label {
+			out = append(out, l)
 		}
-		if !match && !IsInsidePath(pattern, file) {
-			out = append(out, file)
+	}
+	return out
+}
+
+// ContainsLogin returns true if the list of logins contains the given login (case insensitive).
+func ContainsLogin(logins []string, login string) bool {
+	for _, l := range logins {
+		if strings.EqualFold(l, login) {
+			return true
 		}
 	}
-	return out, nil
+	return false
+}
+
+// FilterIgnorePath ...
+func FilterIgnorePath(files []string, pattern string) ([]string, error) {
+	return FilterIgnorePaths(files, []string{pattern})
 }
 
 // FilterPath ...
 func FilterPath(files []string, pattern string) ([]string, error) {
+	return FilterPaths(files, []string{pattern})
+}
+
+// FilterPaths returns the files that match the patterns. Patterns prefixed with ! exclude
+// files that were matched by an earlier pattern, i.e. the last matching pattern wins.
+func FilterPaths(files []string, patterns []string) ([]string, error) {
+	return filterPaths(files, patterns, true)
+}
+
+// FilterIgnorePaths returns the files that are not ignored by the patterns. Patterns prefixed
+// with ! include files that were ignored by an earlier pattern, i.e. the last matching pattern wins.
+func FilterIgnorePaths(files []string, patterns []string) ([]string, error) {
+	return filterPaths(files, patterns, false)
+}
+
+func filterPaths(files []string, patterns []string, keep bool) ([]string, error) {
 	var out []string
 	for _, file := range files {
-		match, err := filepath.Match(pattern, file)
-		if err != nil {
-			return nil, err
+		matched := false
+		for _, pattern := range patterns {
+			negated := strings.HasPrefix(pattern, "!")
+			match, err := MatchPath(strings.TrimPrefix(pattern, "!"), file)
+			if err != nil {
+				return nil, err
+			}
+			if match {
+				matched = !negated
+			}
 		}
-		if match || IsInsidePath(pattern, file) {
+		if matched == keep {
 			out = append(out, file)
 		}
 	}
 	return out, nil
 }
 
+// MatchPath returns true if the file matches the pattern (see path.Match), or if it is inside a
+// path that matches the pattern (e.g. docs or docs/ match docs/index.md). Patterns containing **
+// match any number of directories, e.g. services/**/*.go matches all Go files in the services
+// directory, **/docs all files in docs directories, and **/testdata/** all files in testdata directories.
+func MatchPath(pattern, file string) (bool, error) {
+	if IsInsidePath(pattern, file) {
+		return true, nil
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(file, "/"))
+}
+
+// matchSegments matches the path segments of a file against those of a pattern, where **
+// matches zero or more segments. A pattern that matches the leading segments of the file
+// only matches the file if its last segment is a directory name without wildcards (e.g.
+// **/docs), so test/* does not match files in subdirectories of test.
+func matchSegments(pattern, file []string) (bool, error) {
+	last := ""
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(file); i++ {
+				match, err := matchSegments(pattern[1:], file[i:])
+				if err != nil || match {
+					return match, err
+				}
+			}
+			return false, nil
+		}
+		if len(file) == 0 {
+			return false, nil
+		}
+		// A trailing slash only matches files inside the directory.
+		if pattern[0] == "" && len(pattern) == 1 {
+			return true, nil
+		}
+		match, err := path.Match(pattern[0], file[0])
+		if err != nil || !match {
+			return false, err
+		}
+		last, pattern, file = pattern[0], pattern[1:], file[1:]
+	}
+	return len(file) == 0 || (last != "" && !strings.ContainsAny(last, `*?[\`)), nil
+}
+
 // IsInsidePath checks whether the child path is inside the parent path.
 //
 // /foo/bar is inside /foo, but /foobar is not inside /foo.
@@ -1,9 +1,14 @@
 package resource_test
 
 import (
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/shurcooL/githubv4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	resource "github.com/telia-oss/github-pr-resource"
 	"github.com/telia-oss/github-pr-resource/fakes"
 )
@@ -20,15 +25,188 @@ var (
 		createTestPR(8, "master", false, false, 1, []string{"wontfix"}),
 		createTestPR(9, "master", false, false, 0, nil),
 	}
+
+	readyForReviewTime = time.Now().Add(-time.Hour)
+
+	draftPullRequests = []*resource.PullRequest{
+		withTestPR(createTestPR(1, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.IsDraft = true }),
+		createTestPR(2, "master", false, false, 0, nil),
+		withTestPR(createTestPR(3, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.ReadyForReviewTime = readyForReviewTime }),
+	}
+
+	authorPullRequests = []*resource.PullRequest{
+		withTestPR(createTestPR(1, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.Author.Login = "dependabot" }),
+		createTestPR(2, "master", false, false, 0, nil),
+		createTestPR(3, "master", false, false, 0, nil),
+	}
+
+	triggerCommentTime = time.Now().Add(-2 * time.Hour)
+
+	commentPullRequests = []*resource.PullRequest{
+		createTestPR(1, "master", false, false, 0, nil),
+		withTestPR(createTestPR(2, "master", false, false, 0, nil), func(p *resource.PullRequest) {
+			p.Comments = []resource.CommentObject{
+				createTestComment(100, "/retest", "outsider", resource.CommentAuthorAssociationNone, time.Now().Add(-3*time.Hour)),
+				createTestComment(101, "/retest please", "collaborator", resource.CommentAuthorAssociationCollaborator, triggerCommentTime),
+				createTestComment(102, "lgtm", "member", resource.CommentAuthorAssociationMember, time.Now().Add(-time.Hour)),
+			}
+		}),
+		withTestPR(createTestPR(3, "master", false, false, 0, nil), func(p *resource.PullRequest) {
+			p.Comments = []resource.CommentObject{
+				createTestComment(103, "/retest", "owner", resource.CommentAuthorAssociationOwner, time.Now().AddDate(0, 0, -4)),
+			}
+		}),
+	}
+
+	mergeGroupTime = time.Now().Add(-time.Hour)
+
+	everyCommitPullRequests = []*resource.PullRequest{
+		createTestPR(1, "master", false, false, 0, nil),
+		createTestPR(2, "master", false, false, 0, nil),
+	}
+
+	earlierCommitTime = time.Now().Add(-36 * time.Hour)
+
+	forcePushTime = time.Now().Add(-time.Hour)
+
+	updatedPullRequests = []*resource.PullRequest{
+		withTestPR(createTestPR(1, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.UpdatedAt = forcePushTime }),
+		withTestPR(createTestPR(2, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.UpdatedAt = forcePushTime }),
+		withTestPR(createTestPR(3, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.UpdatedAt = forcePushTime }),
+		createTestPR(4, "master", false, false, 0, nil),
+	}
+
+	pushedPullRequests = []*resource.PullRequest{
+		withTestPR(createTestPR(1, "master", false, false, 0, nil), func(p *resource.PullRequest) {
+			p.UpdatedAt = forcePushTime
+			p.PushedAfter = p.Tip.CommittedDate.Time
+			p.PushesListed = true
+		}),
+		withTestPR(createTestPR(2, "master", false, false, 0, nil), func(p *resource.PullRequest) {
+			p.UpdatedAt = forcePushTime
+			p.PushedAfter = time.Now().Add(-2 * time.Hour)
+			p.PushesListed = true
+		}),
+		withTestPR(createTestPR(3, "master", false, false, 0, nil), func(p *resource.PullRequest) {
+			p.UpdatedAt = forcePushTime
+			p.PushedAfter = p.Tip.CommittedDate.Time
+			p.PushesListed = true
+		}),
+	}
+
+	conflictingPullRequests = []*resource.PullRequest{
+		withTestPR(createTestPR(1, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.Mergeable = resource.MergeableStateConflicting }),
+		createTestPR(2, "master", false, false, 0, nil),
+		createTestPR(3, "master", false, false, 0, nil),
+	}
+
+	filePullRequests = []*resource.PullRequest{
+		withTestPR(createTestPR(1, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.Files = []string{"terraform/main.tf"} }),
+		withTestPR(createTestPR(2, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.Files = []string{} }),
+		createTestPR(3, "master", false, false, 0, nil),
+		createTestPR(4, "master", false, false, 0, nil),
+	}
+
+	labeledTime   = time.Now().Add(-time.Hour)
+	unlabeledTime = time.Now().Add(-2 * time.Hour)
+
+	labelPullRequests = []*resource.PullRequest{
+		createTestPR(1, "master", false, false, 0, []string{"ready-for-ci", "backend", "do-not-build"}),
+		createTestPR(2, "master", false, false, 0, []string{"ready-for-ci"}),
+		withTestPR(createTestPR(3, "master", false, false, 0, []string{"ready-for-ci", "backend"}), func(p *resource.PullRequest) {
+			p.LabelEvents = []resource.LabelEvent{
+				{Name: "ready-for-ci", Added: true, CreatedAt: time.Now().AddDate(0, 0, -5)},
+				{Name: "backend", Added: true, CreatedAt: labeledTime},
+			}
+		}),
+		createTestPR(4, "master", false, false, 0, nil),
+		withTestPR(createTestPR(5, "master", false, false, 0, []string{"ready-for-ci", "backend"}), func(p *resource.PullRequest) {
+			p.LabelEvents = []resource.LabelEvent{
+				{Name: "ready-for-ci", Added: true, CreatedAt: time.Now().AddDate(0, 0, -7)},
+				{Name: "backend", Added: true, CreatedAt: time.Now().AddDate(0, 0, -7)},
+				{Name: "do-not-build", Added: true, CreatedAt: time.Now().AddDate(0, 0, -6)},
+				{Name: "do-not-build", Added: false, CreatedAt: unlabeledTime},
+			}
+		}),
+	}
+
+	statusPassedTime = time.Now().Add(-time.Hour)
+
+	statusPullRequests = []*resource.PullRequest{
+		withTestPR(createTestPR(1, "master", false, false, 0, nil), func(p *resource.PullRequest) {
+			p.Statuses = []resource.Status{
+				{Context: "concourse-ci/unit-test", State: "pending"},
+			}
+		}),
+		withTestPR(createTestPR(2, "master", false, false, 0, nil), func(p *resource.PullRequest) {
+			p.Statuses = []resource.Status{
+				{Context: "concourse-ci/unit-test", State: "success", UpdatedAt: time.Now().AddDate(0, 0, -3)},
+				{Context: "concourse-ci/lint", State: "failure"},
+			}
+		}),
+		withTestPR(createTestPR(3, "master", false, false, 0, nil), func(p *resource.PullRequest) {
+			p.Statuses = []resource.Status{
+				{Context: "concourse-ci/unit-test", State: "success", UpdatedAt: statusPassedTime},
+			}
+		}),
+		createTestPR(4, "master", false, false, 0, nil),
+	}
+
+	baseCommitTime = time.Now().Add(-time.Hour)
+
+	basePullRequests = []*resource.PullRequest{
+		withTestPR(createTestPR(1, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.BaseTip = createTestCommit("base2", baseCommitTime) }),
+		withTestPR(createTestPR(2, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.BaseTip = createTestCommit("base2", baseCommitTime) }),
+		withTestPR(createTestPR(3, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.BaseTip = createTestCommit("base2", baseCommitTime) }),
+		withTestPR(createTestPR(4, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.BaseTip = createTestCommit("base2", baseCommitTime) }),
+		withTestPR(createTestPR(5, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.BaseTip = createTestCommit("base2", baseCommitTime) }),
+	}
+
+	forcePushedPullRequests = []*resource.PullRequest{
+		createTestPR(1, "master", false, false, 0, nil),
+		createTestPR(2, "master", false, false, 0, nil),
+		withTestPR(createTestPR(5, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.PushedTime = forcePushTime }),
+	}
+
+	mergeQueuePullRequests = []*resource.PullRequest{
+		createTestPR(1, "master", false, false, 0, nil),
+		withTestPR(createTestPR(2, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.MergeGroup = createTestCommit("group2", mergeGroupTime) }),
+		withTestPR(createTestPR(3, "master", false, false, 0, nil), func(p *resource.PullRequest) { p.MergeGroup = createTestCommit("group3", time.Now().AddDate(0, 0, -4)) }),
+	}
 )
 
+// withTestPR returns the pull request after setting the fields used by a test.
+func withTestPR(p *resource.PullRequest, set func(p *resource.PullRequest)) *resource.PullRequest {
+	set(p)
+	return p
+}
+
+func createTestCommit(oid string, committedAt time.Time) resource.CommitObject {
+	return resource.CommitObject{OID: oid, CommittedDate: githubv4.DateTime{Time: committedAt}}
+}
+
+func createTestComment(id int64, body, login string, association resource.CommentAuthorAssociation, createdAt time.Time) resource.CommentObject {
+	c := resource.CommentObject{
+		DatabaseId:        id,
+		Body:              body,
+		CreatedAt:         createdAt,
+		AuthorAssociation: association,
+	}
+	c.Author.Login = login
+	return c
+}
+
 func TestCheck(t *testing.T) {
 	tests := []struct {
 		description  string
 		source       resource.Source
 		version      resource.Version
 		files        [][]string
+		compared     [][]string
+		previous     []string
+		commits      [][]resource.CommitObject
 		pullRequests []*resource.PullRequest
+		teamMembers  []string
 		expected     resource.CheckResponse
 	}{
 		{
@@ -185,16 +363,423 @@ func TestCheck(t *testing.T) {
 				resource.NewVersion(testPullRequests[6]),
 			},
 		},
+
+		{
+			description: "check ignores draft pull requests when specified",
+			source: resource.Source{
+				Repository:   "itsdalmo/test-repository",
+				AccessToken:  "oauthtoken",
+				IgnoreDrafts: true,
+			},
+			version:      resource.NewVersion(draftPullRequests[2]),
+			pullRequests: draftPullRequests,
+			expected: resource.CheckResponse{
+				resource.NewVersion(draftPullRequests[1]),
+				resource.Version{PR: "3", Commit: "oid3", CommittedDate: readyForReviewTime},
+			},
+		},
+
+		{
+			description: "check returns a new version when a draft is marked as ready for review",
+			source: resource.Source{
+				Repository:   "itsdalmo/test-repository",
+				AccessToken:  "oauthtoken",
+				IgnoreDrafts: true,
+			},
+			version:      resource.NewVersion(draftPullRequests[1]),
+			pullRequests: draftPullRequests,
+			expected: resource.CheckResponse{
+				resource.Version{PR: "3", Commit: "oid3", CommittedDate: readyForReviewTime},
+			},
+		},
+
+		{
+			description: "check does not use the ready for review time unless drafts are ignored",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version:      resource.NewVersion(draftPullRequests[1]),
+			pullRequests: draftPullRequests,
+			expected: resource.CheckResponse{
+				resource.NewVersion(draftPullRequests[0]),
+			},
+		},
+
+		{
+			description: "check ignores pull requests from ignored authors",
+			source: resource.Source{
+				Repository:     "itsdalmo/test-repository",
+				AccessToken:    "oauthtoken",
+				IgnoredAuthors: []string{"dependabot"},
+			},
+			version:      resource.NewVersion(authorPullRequests[2]),
+			pullRequests: authorPullRequests,
+			expected: resource.CheckResponse{
+				resource.NewVersion(authorPullRequests[1]),
+			},
+		},
+
+		{
+			description: "check only returns pull requests from allowed authors",
+			source: resource.Source{
+				Repository:     "itsdalmo/test-repository",
+				AccessToken:    "oauthtoken",
+				AllowedAuthors: []string{"dependabot"},
+			},
+			version:      resource.Version{},
+			pullRequests: authorPullRequests,
+			expected: resource.CheckResponse{
+				resource.NewVersion(authorPullRequests[0]),
+			},
+		},
+
+		{
+			description: "check only returns pull requests opened by members of allowed teams",
+			source: resource.Source{
+				Repository:   "itsdalmo/test-repository",
+				AccessToken:  "oauthtoken",
+				AllowedTeams: []string{"itsdalmo/maintainers"},
+			},
+			version:      resource.Version{},
+			pullRequests: authorPullRequests,
+			teamMembers:  []string{"login1", "LOGIN3"},
+			expected: resource.CheckResponse{
+				resource.NewVersion(authorPullRequests[2]),
+			},
+		},
+
+		{
+			description: "check returns a new version when an authorised user comments with the trigger phrase",
+			source: resource.Source{
+				Repository:    "itsdalmo/test-repository",
+				AccessToken:   "oauthtoken",
+				TriggerPhrase: "^/retest",
+			},
+			version:      resource.NewVersion(commentPullRequests[0]),
+			pullRequests: commentPullRequests,
+			expected: resource.CheckResponse{
+				resource.Version{PR: "2", Commit: "oid2", CommittedDate: triggerCommentTime, Comment: "101"},
+			},
+		},
+
+		{
+			description: "check does not trigger on comments from ignored authors",
+			source: resource.Source{
+				Repository:     "itsdalmo/test-repository",
+				AccessToken:    "oauthtoken",
+				TriggerPhrase:  "^/retest",
+				IgnoredAuthors: []string{"collaborator"},
+			},
+			version:      resource.NewVersion(commentPullRequests[0]),
+			pullRequests: commentPullRequests,
+			expected: resource.CheckResponse{
+				resource.NewVersion(commentPullRequests[0]),
+			},
+		},
+
+		{
+			description: "check does not trigger on comments unless a trigger phrase is specified",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version:      resource.NewVersion(commentPullRequests[0]),
+			pullRequests: commentPullRequests,
+			expected: resource.CheckResponse{
+				resource.NewVersion(commentPullRequests[0]),
+			},
+		},
+
+		{
+			description: "check only lists the modified files of pull requests with too many files to include",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				Paths:       []string{"terraform/"},
+			},
+			version:      resource.NewVersion(filePullRequests[3]),
+			pullRequests: filePullRequests,
+			files: [][]string{
+				{"terraform/variables.tf"},
+			},
+			expected: resource.CheckResponse{
+				resource.NewVersion(filePullRequests[2]),
+				resource.NewVersion(filePullRequests[0]),
+			},
+		},
+
+		{
+			description: "check only matches paths against the files changed since the previous version when specified",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				Paths:       []string{"terraform/"},
+				PathsScope:  "latest_push",
+			},
+			version:      resource.NewVersion(testPullRequests[3]),
+			pullRequests: testPullRequests,
+			previous:     []string{"old2", ""},
+			compared: [][]string{
+				{"terraform/main.tf"},
+			},
+			files: [][]string{
+				{"terraform/variables.tf"},
+			},
+			expected: resource.CheckResponse{
+				resource.NewVersion(testPullRequests[2]),
+				resource.NewVersion(testPullRequests[1]),
+			},
+		},
+
+		{
+			description: "check compares against the previous version if it is for the same pull request",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				IgnorePaths: []string{"docs/"},
+				PathsScope:  "latest_push",
+			},
+			version:      resource.Version{PR: "2", Commit: "old2", CommittedDate: testPullRequests[2].Tip.CommittedDate.Time},
+			pullRequests: testPullRequests,
+			compared: [][]string{
+				{"docs/README.md"},
+			},
+			expected: resource.CheckResponse{
+				resource.Version{PR: "2", Commit: "old2", CommittedDate: testPullRequests[2].Tip.CommittedDate.Time},
+			},
+		},
+
+		{
+			description: "check returns pull requests with all of the labels and none of the ignored labels",
+			source: resource.Source{
+				Repository:   "itsdalmo/test-repository",
+				AccessToken:  "oauthtoken",
+				Labels:       []string{"ready-for-ci", "backend"},
+				LabelsMatch:  "all",
+				IgnoreLabels: []string{"do-not-build"},
+			},
+			version:      resource.NewVersion(labelPullRequests[3]),
+			pullRequests: labelPullRequests,
+			expected: resource.CheckResponse{
+				resource.Version{PR: "5", Commit: "oid5", CommittedDate: unlabeledTime},
+				resource.Version{PR: "3", Commit: "oid3", CommittedDate: labeledTime},
+			},
+		},
+
+		{
+			description: "check returns a new version when labels make a pull request newly eligible",
+			source: resource.Source{
+				Repository:   "itsdalmo/test-repository",
+				AccessToken:  "oauthtoken",
+				Labels:       []string{"ready-for-ci", "backend"},
+				IgnoreLabels: []string{"do-not-build"},
+			},
+			version:      resource.NewVersion(labelPullRequests[3]),
+			pullRequests: labelPullRequests,
+			expected: resource.CheckResponse{
+				resource.NewVersion(labelPullRequests[2]),
+				resource.NewVersion(labelPullRequests[1]),
+				resource.Version{PR: "5", Commit: "oid5", CommittedDate: unlabeledTime},
+			},
+		},
+
+		{
+			description: "check returns pull requests once the required statuses have passed",
+			source: resource.Source{
+				Repository:       "itsdalmo/test-repository",
+				AccessToken:      "oauthtoken",
+				RequiredStatuses: []resource.RequiredStatus{{Context: "concourse-ci/unit-test"}},
+			},
+			version:      resource.NewVersion(statusPullRequests[3]),
+			pullRequests: statusPullRequests,
+			expected: resource.CheckResponse{
+				resource.NewVersion(statusPullRequests[1]),
+				resource.Version{PR: "3", Commit: "oid3", CommittedDate: statusPassedTime},
+			},
+		},
+
+		{
+			description: "check requires all statuses matching a required context to be in the required state",
+			source: resource.Source{
+				Repository:       "itsdalmo/test-repository",
+				AccessToken:      "oauthtoken",
+				RequiredStatuses: []resource.RequiredStatus{{Context: "concourse-ci/.*", State: "success"}},
+			},
+			version:      resource.NewVersion(statusPullRequests[3]),
+			pullRequests: statusPullRequests,
+			expected: resource.CheckResponse{
+				resource.Version{PR: "3", Commit: "oid3", CommittedDate: statusPassedTime},
+			},
+		},
+
+		{
+			description: "check ignores pull requests with merge conflicts when specified",
+			source: resource.Source{
+				Repository:        "itsdalmo/test-repository",
+				AccessToken:       "oauthtoken",
+				IgnoreConflicting: true,
+			},
+			version:      resource.NewVersion(conflictingPullRequests[2]),
+			pullRequests: conflictingPullRequests,
+			expected: resource.CheckResponse{
+				resource.NewVersion(conflictingPullRequests[1]),
+			},
+		},
+
+		{
+			description: "check returns a new version when an older commit is force pushed",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version:      resource.NewVersion(forcePushedPullRequests[1]),
+			pullRequests: forcePushedPullRequests,
+			expected: resource.CheckResponse{
+				resource.NewVersion(forcePushedPullRequests[0]),
+				resource.Version{PR: "5", Commit: "oid5", CommittedDate: forcePushTime},
+			},
+		},
+
+		{
+			description: "check returns new versions for a limited number of pull requests when the base branch moves",
+			source: resource.Source{
+				Repository:            "itsdalmo/test-repository",
+				AccessToken:           "oauthtoken",
+				TrackBaseBranch:       true,
+				MaxBaseBranchRebuilds: 2,
+			},
+			version:      resource.Version{PR: "2", Commit: "oid2", CommittedDate: basePullRequests[1].Tip.CommittedDate.Time, BaseCommit: "base1"},
+			pullRequests: basePullRequests,
+			expected: resource.CheckResponse{
+				resource.Version{PR: "1", Commit: "oid1", CommittedDate: baseCommitTime, BaseCommit: "base2"},
+				resource.Version{PR: "3", Commit: "oid3", CommittedDate: baseCommitTime, BaseCommit: "base2"},
+				resource.Version{PR: "2", Commit: "oid2", CommittedDate: baseCommitTime, BaseCommit: "base2"},
+			},
+		},
+
+		{
+			description: "check dates a changed tip by when the pull request was updated if it is older than the last version",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version:      resource.NewVersion(updatedPullRequests[0]),
+			pullRequests: updatedPullRequests,
+			previous:     []string{"oid2-previous", "oid3"},
+			expected: resource.CheckResponse{
+				resource.Version{PR: "2", Commit: "oid2", CommittedDate: forcePushTime},
+			},
+		},
+
+		{
+			description: "check uses the pushes to tell whether the tip has changed without looking it up",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version:      resource.NewVersion(pushedPullRequests[0]),
+			pullRequests: pushedPullRequests,
+			expected: resource.CheckResponse{
+				resource.Version{PR: "2", Commit: "oid2", CommittedDate: forcePushTime},
+			},
+		},
+
+		{
+			description: "check does not look up the previous tip of pull requests that were only commented on",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version:      resource.NewVersion(pushedPullRequests[0]),
+			pullRequests: []*resource.PullRequest{pushedPullRequests[2]},
+			expected: resource.CheckResponse{
+				resource.NewVersion(pushedPullRequests[0]),
+			},
+		},
+
+		{
+			description: "check returns a version for every commit since the last when specified",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				EveryCommit: true,
+			},
+			version:      resource.NewVersion(createTestPR(3, "master", false, false, 0, nil)),
+			pullRequests: everyCommitPullRequests,
+			previous:     []string{"oid1-previous", "oid2-previous"},
+			commits: [][]resource.CommitObject{
+				{
+					{OID: "oid1a", CommittedDate: githubv4.DateTime{Time: earlierCommitTime}, Message: "commit message1a"},
+					everyCommitPullRequests[0].Tip,
+				},
+				{
+					{OID: "oid2a", CommittedDate: githubv4.DateTime{Time: time.Now().Add(-60 * time.Hour)}, Message: "[skip ci] commit message2a"},
+					everyCommitPullRequests[1].Tip,
+				},
+			},
+			expected: resource.CheckResponse{
+				resource.NewVersion(everyCommitPullRequests[1]),
+				resource.Version{PR: "1", Commit: "oid1a", CommittedDate: everyCommitPullRequests[0].Tip.CommittedDate.Time},
+				resource.NewVersion(everyCommitPullRequests[0]),
+			},
+		},
+
+		{
+			description: "check returns a version for every commit since the previous version of the same pull request",
+			source: resource.Source{
+				Repository:      "itsdalmo/test-repository",
+				AccessToken:     "oauthtoken",
+				EveryCommit:     true,
+				TrackBaseBranch: true,
+			},
+			version:      resource.Version{PR: "2", Commit: "oid2-previous", CommittedDate: time.Now().AddDate(0, 0, -3), BaseCommit: "base1"},
+			pullRequests: []*resource.PullRequest{basePullRequests[1]},
+			commits: [][]resource.CommitObject{
+				{
+					{OID: "oid2a", CommittedDate: githubv4.DateTime{Time: earlierCommitTime}, Message: "commit message2a"},
+					basePullRequests[1].Tip,
+				},
+			},
+			expected: resource.CheckResponse{
+				resource.Version{PR: "2", Commit: "oid2a", CommittedDate: baseCommitTime, BaseCommit: "base2"},
+				resource.Version{PR: "2", Commit: "oid2", CommittedDate: baseCommitTime, BaseCommit: "base2"},
+			},
+		},
+
+		{
+			description: "check only returns merge groups when merge_queue is specified",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				MergeQueue:  true,
+			},
+			version:      resource.Version{PR: "3", Commit: "oid3", CommittedDate: mergeQueuePullRequests[2].Tip.CommittedDate.Time, MergeGroup: "group3"},
+			pullRequests: mergeQueuePullRequests,
+			expected: resource.CheckResponse{
+				resource.Version{PR: "2", Commit: "oid2", CommittedDate: mergeGroupTime, MergeGroup: "group2"},
+			},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
-			github := new(fakes.FakeGithub)
+			github := new(fakes.FakeProvider)
 			github.ListOpenPullRequestsReturns(tc.pullRequests, nil)
+			github.ListTeamMembersReturns(tc.teamMembers, nil)
 
 			for i, file := range tc.files {
 				github.ListModifiedFilesReturnsOnCall(i, file, nil)
 			}
+			for i, file := range tc.compared {
+				github.ListModifiedFilesBetweenReturnsOnCall(i, file, nil)
+			}
+			for i, sha := range tc.previous {
+				github.GetPullRequestCommitBeforeReturnsOnCall(i, sha, nil)
+			}
+			for i, commits := range tc.commits {
+				github.ListPullRequestCommitsReturnsOnCall(i, commits, nil)
+			}
 
 			input := resource.CheckRequest{Source: tc.source, Version: tc.version}
 			output, err := resource.Check(input, github)
@@ -203,10 +788,89 @@ func TestCheck(t *testing.T) {
 				assert.Equal(t, tc.expected, output)
 			}
 			assert.Equal(t, 1, github.ListOpenPullRequestsCallCount())
+			assert.Equal(t, len(tc.source.AllowedTeams), github.ListTeamMembersCallCount())
+			assert.Equal(t, len(tc.previous), github.GetPullRequestCommitBeforeCallCount())
+			if tc.source.PathsScope != "" {
+				assert.Equal(t, len(tc.compared), github.ListModifiedFilesBetweenCallCount())
+			}
+			if tc.commits != nil {
+				for i := 0; i < github.ListPullRequestCommitsCallCount(); i++ {
+					expected := tc.version.Commit
+					if len(tc.previous) > 0 {
+						expected = tc.previous[i]
+					}
+					_, after := github.ListPullRequestCommitsArgsForCall(i)
+					assert.Equal(t, expected, after)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckConcurrency(t *testing.T) {
+	var pulls []*resource.PullRequest
+	for i := 1; i <= 20; i++ {
+		pulls = append(pulls, createTestPR(i, "master", false, false, 0, nil))
+	}
+
+	check := func(concurrency int) resource.CheckResponse {
+		github := new(fakes.FakeProvider)
+		github.ListOpenPullRequestsReturns(pulls, nil)
+		github.ListModifiedFilesCalls(func(number int) ([]string, error) {
+			if number%2 == 0 {
+				return []string{"terraform/main.tf"}, nil
+			}
+			return []string{"README.md"}, nil
 		})
+
+		source := resource.Source{
+			Repository:       "itsdalmo/test-repository",
+			AccessToken:      "oauthtoken",
+			Paths:            []string{"terraform/"},
+			CheckConcurrency: concurrency,
+		}
+		output, err := resource.Check(resource.CheckRequest{Source: source, Version: resource.NewVersion(pulls[19])}, github)
+		require.NoError(t, err)
+		assert.Equal(t, 19, github.ListModifiedFilesCallCount())
+		return output
+	}
+
+	expected := check(1)
+	assert.Len(t, expected, 9)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, expected, check(8))
 	}
 }
 
+func TestCheckConcurrencyErrors(t *testing.T) {
+	pulls := []*resource.PullRequest{
+		createTestPR(1, "master", false, false, 0, nil),
+		createTestPR(2, "master", false, false, 0, nil),
+		createTestPR(3, "master", false, false, 0, nil),
+	}
+
+	// Wait for both calls to start, so both of them fail.
+	var started sync.WaitGroup
+	started.Add(2)
+
+	github := new(fakes.FakeProvider)
+	github.ListOpenPullRequestsReturns(pulls, nil)
+	github.ListModifiedFilesCalls(func(number int) ([]string, error) {
+		started.Done()
+		started.Wait()
+		return nil, fmt.Errorf("pull request %d is broken", number)
+	})
+
+	source := resource.Source{
+		Repository:       "itsdalmo/test-repository",
+		AccessToken:      "oauthtoken",
+		Paths:            []string{"terraform/"},
+		CheckConcurrency: 2,
+	}
+	_, err := resource.Check(resource.CheckRequest{Source: source, Version: resource.NewVersion(pulls[2])}, github)
+	assert.EqualError(t, err, "failed to check 2 pull requests: #1: failed to list modified files: pull request 1 is broken; #2: failed to list modified files: pull request 2 is broken")
+}
+
 func TestContainsSkipCI(t *testing.T) {
 	tests := []struct {
 		description string
@@ -258,6 +922,116 @@ func TestContainsSkipCI(t *testing.T) {
 	}
 }
 
+func TestMatchLabels(t *testing.T) {
+	tests := []struct {
+		description string
+		labels      []string
+		wanted      []string
+		ignored     []string
+		matchAll    bool
+		want        bool
+	}{
+		{
+			description: "matches when nothing is wanted",
+			labels:      []string{"bug"},
+			want:        true,
+		},
+		{
+			description: "matches any of the wanted labels",
+			labels:      []string{"bug"},
+			wanted:      []string{"bug", "enhancement"},
+			want:        true,
+		},
+		{
+			description: "does not match when none of the labels are wanted",
+			labels:      []string{"wontfix"},
+			wanted:      []string{"bug", "enhancement"},
+			want:        false,
+		},
+		{
+			description: "matches all of the wanted labels",
+			labels:      []string{"bug", "enhancement", "wontfix"},
+			wanted:      []string{"bug", "enhancement"},
+			matchAll:    true,
+			want:        true,
+		},
+		{
+			description: "does not match when some of the wanted labels are missing",
+			labels:      []string{"bug"},
+			wanted:      []string{"bug", "enhancement"},
+			matchAll:    true,
+			want:        false,
+		},
+		{
+			description: "does not match ignored labels",
+			labels:      []string{"bug", "wontfix"},
+			wanted:      []string{"bug"},
+			ignored:     []string{"wontfix"},
+			want:        false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got := resource.MatchLabels(tc.labels, tc.wanted, tc.ignored, tc.matchAll)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRequiredStatusesPassed(t *testing.T) {
+	statuses := []resource.Status{
+		{Context: "concourse-ci/unit-test", State: "success"},
+		{Context: "concourse-ci/integration-test", State: "pending"},
+		{Context: "lint", State: "failure"},
+	}
+
+	tests := []struct {
+		description string
+		required    []resource.RequiredStatus
+		want        bool
+	}{
+		{
+			description: "passes when nothing is required",
+			want:        true,
+		},
+		{
+			description: "passes when the context has the required state",
+			required:    []resource.RequiredStatus{{Context: "concourse-ci/unit-test"}, {Context: "lint", State: "FAILURE"}},
+			want:        true,
+		},
+		{
+			description: "fails when the context does not have the required state",
+			required:    []resource.RequiredStatus{{Context: "concourse-ci/integration-test"}},
+			want:        false,
+		},
+		{
+			description: "fails when the context is missing",
+			required:    []resource.RequiredStatus{{Context: "deploy"}},
+			want:        false,
+		},
+		{
+			description: "matches the whole context",
+			required:    []resource.RequiredStatus{{Context: "unit-test"}},
+			want:        false,
+		},
+		{
+			description: "fails when any of the matching contexts does not have the required state",
+			required:    []resource.RequiredStatus{{Context: "concourse-ci/.+"}},
+			want:        false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, _, err := resource.RequiredStatusesPassed(statuses, tc.required)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
 func TestFilterPath(t *testing.T) {
 	cases := []struct {
 		description string
@@ -315,6 +1089,96 @@ func TestFilterPath(t *testing.T) {
 				"foo/a/b/c/d.txt",
 			},
 		},
+		{
+			description: "matches any number of directories with doublestar",
+			pattern:     "services/**/*.go",
+			files: []string{
+				"services/main.go",
+				"services/api/v1/handler.go",
+				"services/api/README.md",
+				"cmd/main.go",
+			},
+			want: []string{
+				"services/main.go",
+				"services/api/v1/handler.go",
+			},
+		},
+		{
+			description: "matches everything inside directories with doublestar",
+			pattern:     "**/testdata/**",
+			files: []string{
+				"testdata/a.json",
+				"pkg/foo/testdata/b/c.json",
+				"pkg/foo/testdata.go",
+			},
+			want: []string{
+				"testdata/a.json",
+				"pkg/foo/testdata/b/c.json",
+			},
+		},
+		{
+			description: "matches files inside directories matching a doublestar pattern",
+			pattern:     "**/docs",
+			files: []string{
+				"docs/index.md",
+				"a/docs/x.md",
+				"a/docs",
+				"a/docs.md",
+			},
+			want: []string{
+				"docs/index.md",
+				"a/docs/x.md",
+				"a/docs",
+			},
+		},
+		{
+			description: "matches files inside directories matching a doublestar pattern in the middle",
+			pattern:     "services/**/api",
+			files: []string{
+				"services/api/main.go",
+				"services/x/api/main.go",
+				"services/x/apis/main.go",
+				"other/x/api/main.go",
+			},
+			want: []string{
+				"services/api/main.go",
+				"services/x/api/main.go",
+			},
+		},
+		{
+			description: "matches files inside directories matching a doublestar pattern with a trailing slash",
+			pattern:     "**/docs/",
+			files: []string{
+				"a/docs/x.md",
+				"a/docs",
+			},
+			want: []string{
+				"a/docs/x.md",
+			},
+		},
+		{
+			description: "wildcards do not match files in subdirectories",
+			pattern:     "test/*",
+			files: []string{
+				"test/file1.txt",
+				"test/a/file2.txt",
+				"other/file3.txt",
+			},
+			want: []string{
+				"test/file1.txt",
+			},
+		},
+		{
+			description: "a single wildcard only matches files in the root directory",
+			pattern:     "*",
+			files: []string{
+				"README.md",
+				"test/file1.txt",
+			},
+			want: []string{
+				"README.md",
+			},
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.description, func(t *testing.T) {
@@ -394,6 +1258,53 @@ func TestFilterIgnorePath(t *testing.T) {
 	}
 }
 
+func TestFilterPaths(t *testing.T) {
+	files := []string{
+		"docs/index.md",
+		"docs/keep.md",
+		"services/api/main.go",
+		"services/api/main_test.go",
+	}
+
+	cases := []struct {
+		description string
+		patterns    []string
+		want        []string
+		wantIgnored []string
+	}{
+		{
+			description: "matches any of the patterns",
+			patterns:    []string{"docs/", "**/main.go"},
+			want:        []string{"docs/index.md", "docs/keep.md", "services/api/main.go"},
+			wantIgnored: []string{"services/api/main_test.go"},
+		},
+		{
+			description: "excludes files with negated patterns",
+			patterns:    []string{"docs/", "!docs/keep.md"},
+			want:        []string{"docs/index.md"},
+			wantIgnored: []string{"docs/keep.md", "services/api/main.go", "services/api/main_test.go"},
+		},
+		{
+			description: "uses the last matching pattern",
+			patterns:    []string{"services/**", "!**/*_test.go", "services/api/main_test.go"},
+			want:        []string{"services/api/main.go", "services/api/main_test.go"},
+			wantIgnored: []string{"docs/index.md", "docs/keep.md"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			got, err := resource.FilterPaths(files, tc.patterns)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.want, got)
+			}
+			got, err = resource.FilterIgnorePaths(files, tc.patterns)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.wantIgnored, got)
+			}
+		})
+	}
+}
+
 func TestIsInsidePath(t *testing.T) {
 	cases := []struct {
 		description string
@@ -21,11 +21,11 @@ func main() {
 	if err := request.Source.Validate(); err != nil {
 		log.Fatalf("invalid source configuration: %s", err)
 	}
-	github, err := resource.NewGithubClient(&request.Source)
+	manager, err := resource.NewProvider(&request.Source, nil)
 	if err != nil {
-		log.Fatalf("failed to create github manager: %s", err)
+		log.Fatalf("failed to create %s manager: %s", request.Source.ProviderName(), err)
 	}
-	response, err := resource.Check(request, github)
+	response, err := resource.Check(request, manager)
 	if err != nil {
 		log.Fatalf("check failed: %s", err)
 	}
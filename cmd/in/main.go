@@ -25,15 +25,22 @@ func main() {
 	if err := request.Source.Validate(); err != nil {
 		log.Fatalf("invalid source configuration: %s", err)
 	}
-	git, err := resource.NewGitClient(&request.Source, outputDir, os.Stderr)
+	// Responses are only cached by check, since get and put run in new containers.
+	request.Source.HTTPCache, request.Source.HTTPCacheDir = false, ""
+	// The git client and the provider share the token source, so a Github App only mints one installation token.
+	tokenSource, err := resource.NewTokenSource(&request.Source, nil)
+	if err != nil {
+		log.Fatalf("failed to create token source: %s", err)
+	}
+	git, err := resource.NewGitClient(&request.Source, tokenSource, outputDir, os.Stderr)
 	if err != nil {
 		log.Fatalf("failed to create git client: %s", err)
 	}
-	github, err := resource.NewGithubClient(&request.Source)
+	manager, err := resource.NewProvider(&request.Source, tokenSource)
 	if err != nil {
-		log.Fatalf("failed to create github manager: %s", err)
+		log.Fatalf("failed to create %s manager: %s", request.Source.ProviderName(), err)
 	}
-	response, err := resource.Get(request, github, git, outputDir)
+	response, err := resource.Get(request, manager, git, outputDir)
 	if err != nil {
 		log.Fatalf("get failed: %s", err)
 	}
@@ -25,11 +25,13 @@ func main() {
 	if err := request.Source.Validate(); err != nil {
 		log.Fatalf("invalid source configuration: %s", err)
 	}
-	github, err := resource.NewGithubClient(&request.Source)
+	// Responses are only cached by check, since get and put run in new containers.
+	request.Source.HTTPCache, request.Source.HTTPCacheDir = false, ""
+	manager, err := resource.NewProvider(&request.Source, nil)
 	if err != nil {
-		log.Fatalf("failed to create github manager: %s", err)
+		log.Fatalf("failed to create %s manager: %s", request.Source.ProviderName(), err)
 	}
-	response, err := resource.Put(request, github, sourceDir)
+	response, err := resource.Put(request, manager, sourceDir)
 	if err != nil {
 		log.Fatalf("put failed: %s", err)
 	}
@@ -33,6 +33,19 @@ type FakeGit struct {
 	fetchReturnsOnCall map[int]struct {
 		result1 error
 	}
+	FetchRefStub        func(string, string, int) error
+	fetchRefMutex       sync.RWMutex
+	fetchRefArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 int
+	}
+	fetchRefReturns struct {
+		result1 error
+	}
+	fetchRefReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GitCryptUnlockStub        func(string) error
 	gitCryptUnlockMutex       sync.RWMutex
 	gitCryptUnlockArgsForCall []struct {
@@ -115,15 +128,16 @@ func (fake *FakeGit) Checkout(arg1 string, arg2 string) error {
 		arg1 string
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.CheckoutStub
+	fakeReturns := fake.checkoutReturns
 	fake.recordInvocation("Checkout", []interface{}{arg1, arg2})
 	fake.checkoutMutex.Unlock()
-	if fake.CheckoutStub != nil {
-		return fake.CheckoutStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.checkoutReturns
 	return fakeReturns.result1
 }
 
@@ -177,15 +191,16 @@ func (fake *FakeGit) Fetch(arg1 string, arg2 int, arg3 int) error {
 		arg2 int
 		arg3 int
 	}{arg1, arg2, arg3})
+	stub := fake.FetchStub
+	fakeReturns := fake.fetchReturns
 	fake.recordInvocation("Fetch", []interface{}{arg1, arg2, arg3})
 	fake.fetchMutex.Unlock()
-	if fake.FetchStub != nil {
-		return fake.FetchStub(arg1, arg2, arg3)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.fetchReturns
 	return fakeReturns.result1
 }
 
@@ -231,21 +246,85 @@ func (fake *FakeGit) FetchReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeGit) FetchRef(arg1 string, arg2 string, arg3 int) error {
+	fake.fetchRefMutex.Lock()
+	ret, specificReturn := fake.fetchRefReturnsOnCall[len(fake.fetchRefArgsForCall)]
+	fake.fetchRefArgsForCall = append(fake.fetchRefArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	stub := fake.FetchRefStub
+	fakeReturns := fake.fetchRefReturns
+	fake.recordInvocation("FetchRef", []interface{}{arg1, arg2, arg3})
+	fake.fetchRefMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGit) FetchRefCallCount() int {
+	fake.fetchRefMutex.RLock()
+	defer fake.fetchRefMutex.RUnlock()
+	return len(fake.fetchRefArgsForCall)
+}
+
+func (fake *FakeGit) FetchRefCalls(stub func(string, string, int) error) {
+	fake.fetchRefMutex.Lock()
+	defer fake.fetchRefMutex.Unlock()
+	fake.FetchRefStub = stub
+}
+
+func (fake *FakeGit) FetchRefArgsForCall(i int) (string, string, int) {
+	fake.fetchRefMutex.RLock()
+	defer fake.fetchRefMutex.RUnlock()
+	argsForCall := fake.fetchRefArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGit) FetchRefReturns(result1 error) {
+	fake.fetchRefMutex.Lock()
+	defer fake.fetchRefMutex.Unlock()
+	fake.FetchRefStub = nil
+	fake.fetchRefReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGit) FetchRefReturnsOnCall(i int, result1 error) {
+	fake.fetchRefMutex.Lock()
+	defer fake.fetchRefMutex.Unlock()
+	fake.FetchRefStub = nil
+	if fake.fetchRefReturnsOnCall == nil {
+		fake.fetchRefReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.fetchRefReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeGit) GitCryptUnlock(arg1 string) error {
 	fake.gitCryptUnlockMutex.Lock()
 	ret, specificReturn := fake.gitCryptUnlockReturnsOnCall[len(fake.gitCryptUnlockArgsForCall)]
 	fake.gitCryptUnlockArgsForCall = append(fake.gitCryptUnlockArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.GitCryptUnlockStub
+	fakeReturns := fake.gitCryptUnlockReturns
 	fake.recordInvocation("GitCryptUnlock", []interface{}{arg1})
 	fake.gitCryptUnlockMutex.Unlock()
-	if fake.GitCryptUnlockStub != nil {
-		return fake.GitCryptUnlockStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.gitCryptUnlockReturns
 	return fakeReturns.result1
 }
 
@@ -297,15 +376,16 @@ func (fake *FakeGit) Init(arg1 string) error {
 	fake.initArgsForCall = append(fake.initArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.InitStub
+	fakeReturns := fake.initReturns
 	fake.recordInvocation("Init", []interface{}{arg1})
 	fake.initMutex.Unlock()
-	if fake.InitStub != nil {
-		return fake.InitStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.initReturns
 	return fakeReturns.result1
 }
 
@@ -357,15 +437,16 @@ func (fake *FakeGit) Merge(arg1 string) error {
 	fake.mergeArgsForCall = append(fake.mergeArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.MergeStub
+	fakeReturns := fake.mergeReturns
 	fake.recordInvocation("Merge", []interface{}{arg1})
 	fake.mergeMutex.Unlock()
-	if fake.MergeStub != nil {
-		return fake.MergeStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.mergeReturns
 	return fakeReturns.result1
 }
 
@@ -419,15 +500,16 @@ func (fake *FakeGit) Pull(arg1 string, arg2 string, arg3 int) error {
 		arg2 string
 		arg3 int
 	}{arg1, arg2, arg3})
+	stub := fake.PullStub
+	fakeReturns := fake.pullReturns
 	fake.recordInvocation("Pull", []interface{}{arg1, arg2, arg3})
 	fake.pullMutex.Unlock()
-	if fake.PullStub != nil {
-		return fake.PullStub(arg1, arg2, arg3)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.pullReturns
 	return fakeReturns.result1
 }
 
@@ -480,15 +562,16 @@ func (fake *FakeGit) Rebase(arg1 string, arg2 string) error {
 		arg1 string
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.RebaseStub
+	fakeReturns := fake.rebaseReturns
 	fake.recordInvocation("Rebase", []interface{}{arg1, arg2})
 	fake.rebaseMutex.Unlock()
-	if fake.RebaseStub != nil {
-		return fake.RebaseStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.rebaseReturns
 	return fakeReturns.result1
 }
 
@@ -540,15 +623,16 @@ func (fake *FakeGit) RevParse(arg1 string) (string, error) {
 	fake.revParseArgsForCall = append(fake.revParseArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.RevParseStub
+	fakeReturns := fake.revParseReturns
 	fake.recordInvocation("RevParse", []interface{}{arg1})
 	fake.revParseMutex.Unlock()
-	if fake.RevParseStub != nil {
-		return fake.RevParseStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.revParseReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -600,22 +684,6 @@ func (fake *FakeGit) RevParseReturnsOnCall(i int, result1 string, result2 error)
 func (fake *FakeGit) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.checkoutMutex.RLock()
-	defer fake.checkoutMutex.RUnlock()
-	fake.fetchMutex.RLock()
-	defer fake.fetchMutex.RUnlock()
-	fake.gitCryptUnlockMutex.RLock()
-	defer fake.gitCryptUnlockMutex.RUnlock()
-	fake.initMutex.RLock()
-	defer fake.initMutex.RUnlock()
-	fake.mergeMutex.RLock()
-	defer fake.mergeMutex.RUnlock()
-	fake.pullMutex.RLock()
-	defer fake.pullMutex.RUnlock()
-	fake.rebaseMutex.RLock()
-	defer fake.rebaseMutex.RUnlock()
-	fake.revParseMutex.RLock()
-	defer fake.revParseMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
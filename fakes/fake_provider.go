@@ -0,0 +1,1581 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+	"time"
+
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+type FakeProvider struct {
+	AddLabelsStub        func(string, []string, bool) error
+	addLabelsMutex       sync.RWMutex
+	addLabelsArgsForCall []struct {
+		arg1 string
+		arg2 []string
+		arg3 bool
+	}
+	addLabelsReturns struct {
+		result1 error
+	}
+	addLabelsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateReviewStub        func(string, string, resource.Review) error
+	createReviewMutex       sync.RWMutex
+	createReviewArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 resource.Review
+	}
+	createReviewReturns struct {
+		result1 error
+	}
+	createReviewReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeletePreviousCommentsStub        func(string) error
+	deletePreviousCommentsMutex       sync.RWMutex
+	deletePreviousCommentsArgsForCall []struct {
+		arg1 string
+	}
+	deletePreviousCommentsReturns struct {
+		result1 error
+	}
+	deletePreviousCommentsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	EnableAutoMergeStub        func(string, string, resource.Merge) error
+	enableAutoMergeMutex       sync.RWMutex
+	enableAutoMergeArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 resource.Merge
+	}
+	enableAutoMergeReturns struct {
+		result1 error
+	}
+	enableAutoMergeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	EnqueuePullRequestStub        func(string, string) error
+	enqueuePullRequestMutex       sync.RWMutex
+	enqueuePullRequestArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	enqueuePullRequestReturns struct {
+		result1 error
+	}
+	enqueuePullRequestReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetChangedFilesStub        func(string, string) ([]resource.ChangedFileObject, error)
+	getChangedFilesMutex       sync.RWMutex
+	getChangedFilesArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getChangedFilesReturns struct {
+		result1 []resource.ChangedFileObject
+		result2 error
+	}
+	getChangedFilesReturnsOnCall map[int]struct {
+		result1 []resource.ChangedFileObject
+		result2 error
+	}
+	GetCommentStub        func(string, string) (*resource.CommentObject, error)
+	getCommentMutex       sync.RWMutex
+	getCommentArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getCommentReturns struct {
+		result1 *resource.CommentObject
+		result2 error
+	}
+	getCommentReturnsOnCall map[int]struct {
+		result1 *resource.CommentObject
+		result2 error
+	}
+	GetPullRequestStub        func(string, string) (*resource.PullRequest, error)
+	getPullRequestMutex       sync.RWMutex
+	getPullRequestArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getPullRequestReturns struct {
+		result1 *resource.PullRequest
+		result2 error
+	}
+	getPullRequestReturnsOnCall map[int]struct {
+		result1 *resource.PullRequest
+		result2 error
+	}
+	GetPullRequestCommitBeforeStub        func(int, time.Time) (string, error)
+	getPullRequestCommitBeforeMutex       sync.RWMutex
+	getPullRequestCommitBeforeArgsForCall []struct {
+		arg1 int
+		arg2 time.Time
+	}
+	getPullRequestCommitBeforeReturns struct {
+		result1 string
+		result2 error
+	}
+	getPullRequestCommitBeforeReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	ListModifiedFilesStub        func(int) ([]string, error)
+	listModifiedFilesMutex       sync.RWMutex
+	listModifiedFilesArgsForCall []struct {
+		arg1 int
+	}
+	listModifiedFilesReturns struct {
+		result1 []string
+		result2 error
+	}
+	listModifiedFilesReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	ListModifiedFilesBetweenStub        func(string, string) ([]string, error)
+	listModifiedFilesBetweenMutex       sync.RWMutex
+	listModifiedFilesBetweenArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	listModifiedFilesBetweenReturns struct {
+		result1 []string
+		result2 error
+	}
+	listModifiedFilesBetweenReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	ListOpenPullRequestsStub        func() ([]*resource.PullRequest, error)
+	listOpenPullRequestsMutex       sync.RWMutex
+	listOpenPullRequestsArgsForCall []struct {
+	}
+	listOpenPullRequestsReturns struct {
+		result1 []*resource.PullRequest
+		result2 error
+	}
+	listOpenPullRequestsReturnsOnCall map[int]struct {
+		result1 []*resource.PullRequest
+		result2 error
+	}
+	ListPullRequestCommitsStub        func(int, string) ([]resource.CommitObject, error)
+	listPullRequestCommitsMutex       sync.RWMutex
+	listPullRequestCommitsArgsForCall []struct {
+		arg1 int
+		arg2 string
+	}
+	listPullRequestCommitsReturns struct {
+		result1 []resource.CommitObject
+		result2 error
+	}
+	listPullRequestCommitsReturnsOnCall map[int]struct {
+		result1 []resource.CommitObject
+		result2 error
+	}
+	ListTeamMembersStub        func(string, string) ([]string, error)
+	listTeamMembersMutex       sync.RWMutex
+	listTeamMembersArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	listTeamMembersReturns struct {
+		result1 []string
+		result2 error
+	}
+	listTeamMembersReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	MergePullRequestStub        func(string, string, resource.Merge) (string, error)
+	mergePullRequestMutex       sync.RWMutex
+	mergePullRequestArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 resource.Merge
+	}
+	mergePullRequestReturns struct {
+		result1 string
+		result2 error
+	}
+	mergePullRequestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	PostCommentStub        func(string, string) error
+	postCommentMutex       sync.RWMutex
+	postCommentArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	postCommentReturns struct {
+		result1 error
+	}
+	postCommentReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RemoveLabelsStub        func(string, []string) error
+	removeLabelsMutex       sync.RWMutex
+	removeLabelsArgsForCall []struct {
+		arg1 string
+		arg2 []string
+	}
+	removeLabelsReturns struct {
+		result1 error
+	}
+	removeLabelsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateCheckRunStub        func(string, resource.CheckRun) error
+	updateCheckRunMutex       sync.RWMutex
+	updateCheckRunArgsForCall []struct {
+		arg1 string
+		arg2 resource.CheckRun
+	}
+	updateCheckRunReturns struct {
+		result1 error
+	}
+	updateCheckRunReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateCommentStub        func(string, string, string) error
+	updateCommentMutex       sync.RWMutex
+	updateCommentArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	updateCommentReturns struct {
+		result1 error
+	}
+	updateCommentReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateCommitStatusStub        func(string, string, string, string, string, string) error
+	updateCommitStatusMutex       sync.RWMutex
+	updateCommitStatusArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+		arg6 string
+	}
+	updateCommitStatusReturns struct {
+		result1 error
+	}
+	updateCommitStatusReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeProvider) AddLabels(arg1 string, arg2 []string, arg3 bool) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.addLabelsMutex.Lock()
+	ret, specificReturn := fake.addLabelsReturnsOnCall[len(fake.addLabelsArgsForCall)]
+	fake.addLabelsArgsForCall = append(fake.addLabelsArgsForCall, struct {
+		arg1 string
+		arg2 []string
+		arg3 bool
+	}{arg1, arg2Copy, arg3})
+	stub := fake.AddLabelsStub
+	fakeReturns := fake.addLabelsReturns
+	fake.recordInvocation("AddLabels", []interface{}{arg1, arg2Copy, arg3})
+	fake.addLabelsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) AddLabelsCallCount() int {
+	fake.addLabelsMutex.RLock()
+	defer fake.addLabelsMutex.RUnlock()
+	return len(fake.addLabelsArgsForCall)
+}
+
+func (fake *FakeProvider) AddLabelsCalls(stub func(string, []string, bool) error) {
+	fake.addLabelsMutex.Lock()
+	defer fake.addLabelsMutex.Unlock()
+	fake.AddLabelsStub = stub
+}
+
+func (fake *FakeProvider) AddLabelsArgsForCall(i int) (string, []string, bool) {
+	fake.addLabelsMutex.RLock()
+	defer fake.addLabelsMutex.RUnlock()
+	argsForCall := fake.addLabelsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProvider) AddLabelsReturns(result1 error) {
+	fake.addLabelsMutex.Lock()
+	defer fake.addLabelsMutex.Unlock()
+	fake.AddLabelsStub = nil
+	fake.addLabelsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) AddLabelsReturnsOnCall(i int, result1 error) {
+	fake.addLabelsMutex.Lock()
+	defer fake.addLabelsMutex.Unlock()
+	fake.AddLabelsStub = nil
+	if fake.addLabelsReturnsOnCall == nil {
+		fake.addLabelsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addLabelsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) CreateReview(arg1 string, arg2 string, arg3 resource.Review) error {
+	fake.createReviewMutex.Lock()
+	ret, specificReturn := fake.createReviewReturnsOnCall[len(fake.createReviewArgsForCall)]
+	fake.createReviewArgsForCall = append(fake.createReviewArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 resource.Review
+	}{arg1, arg2, arg3})
+	stub := fake.CreateReviewStub
+	fakeReturns := fake.createReviewReturns
+	fake.recordInvocation("CreateReview", []interface{}{arg1, arg2, arg3})
+	fake.createReviewMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) CreateReviewCallCount() int {
+	fake.createReviewMutex.RLock()
+	defer fake.createReviewMutex.RUnlock()
+	return len(fake.createReviewArgsForCall)
+}
+
+func (fake *FakeProvider) CreateReviewCalls(stub func(string, string, resource.Review) error) {
+	fake.createReviewMutex.Lock()
+	defer fake.createReviewMutex.Unlock()
+	fake.CreateReviewStub = stub
+}
+
+func (fake *FakeProvider) CreateReviewArgsForCall(i int) (string, string, resource.Review) {
+	fake.createReviewMutex.RLock()
+	defer fake.createReviewMutex.RUnlock()
+	argsForCall := fake.createReviewArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProvider) CreateReviewReturns(result1 error) {
+	fake.createReviewMutex.Lock()
+	defer fake.createReviewMutex.Unlock()
+	fake.CreateReviewStub = nil
+	fake.createReviewReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) CreateReviewReturnsOnCall(i int, result1 error) {
+	fake.createReviewMutex.Lock()
+	defer fake.createReviewMutex.Unlock()
+	fake.CreateReviewStub = nil
+	if fake.createReviewReturnsOnCall == nil {
+		fake.createReviewReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createReviewReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) DeletePreviousComments(arg1 string) error {
+	fake.deletePreviousCommentsMutex.Lock()
+	ret, specificReturn := fake.deletePreviousCommentsReturnsOnCall[len(fake.deletePreviousCommentsArgsForCall)]
+	fake.deletePreviousCommentsArgsForCall = append(fake.deletePreviousCommentsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.DeletePreviousCommentsStub
+	fakeReturns := fake.deletePreviousCommentsReturns
+	fake.recordInvocation("DeletePreviousComments", []interface{}{arg1})
+	fake.deletePreviousCommentsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) DeletePreviousCommentsCallCount() int {
+	fake.deletePreviousCommentsMutex.RLock()
+	defer fake.deletePreviousCommentsMutex.RUnlock()
+	return len(fake.deletePreviousCommentsArgsForCall)
+}
+
+func (fake *FakeProvider) DeletePreviousCommentsCalls(stub func(string) error) {
+	fake.deletePreviousCommentsMutex.Lock()
+	defer fake.deletePreviousCommentsMutex.Unlock()
+	fake.DeletePreviousCommentsStub = stub
+}
+
+func (fake *FakeProvider) DeletePreviousCommentsArgsForCall(i int) string {
+	fake.deletePreviousCommentsMutex.RLock()
+	defer fake.deletePreviousCommentsMutex.RUnlock()
+	argsForCall := fake.deletePreviousCommentsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeProvider) DeletePreviousCommentsReturns(result1 error) {
+	fake.deletePreviousCommentsMutex.Lock()
+	defer fake.deletePreviousCommentsMutex.Unlock()
+	fake.DeletePreviousCommentsStub = nil
+	fake.deletePreviousCommentsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) DeletePreviousCommentsReturnsOnCall(i int, result1 error) {
+	fake.deletePreviousCommentsMutex.Lock()
+	defer fake.deletePreviousCommentsMutex.Unlock()
+	fake.DeletePreviousCommentsStub = nil
+	if fake.deletePreviousCommentsReturnsOnCall == nil {
+		fake.deletePreviousCommentsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deletePreviousCommentsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) EnableAutoMerge(arg1 string, arg2 string, arg3 resource.Merge) error {
+	fake.enableAutoMergeMutex.Lock()
+	ret, specificReturn := fake.enableAutoMergeReturnsOnCall[len(fake.enableAutoMergeArgsForCall)]
+	fake.enableAutoMergeArgsForCall = append(fake.enableAutoMergeArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 resource.Merge
+	}{arg1, arg2, arg3})
+	stub := fake.EnableAutoMergeStub
+	fakeReturns := fake.enableAutoMergeReturns
+	fake.recordInvocation("EnableAutoMerge", []interface{}{arg1, arg2, arg3})
+	fake.enableAutoMergeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) EnableAutoMergeCallCount() int {
+	fake.enableAutoMergeMutex.RLock()
+	defer fake.enableAutoMergeMutex.RUnlock()
+	return len(fake.enableAutoMergeArgsForCall)
+}
+
+func (fake *FakeProvider) EnableAutoMergeCalls(stub func(string, string, resource.Merge) error) {
+	fake.enableAutoMergeMutex.Lock()
+	defer fake.enableAutoMergeMutex.Unlock()
+	fake.EnableAutoMergeStub = stub
+}
+
+func (fake *FakeProvider) EnableAutoMergeArgsForCall(i int) (string, string, resource.Merge) {
+	fake.enableAutoMergeMutex.RLock()
+	defer fake.enableAutoMergeMutex.RUnlock()
+	argsForCall := fake.enableAutoMergeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProvider) EnableAutoMergeReturns(result1 error) {
+	fake.enableAutoMergeMutex.Lock()
+	defer fake.enableAutoMergeMutex.Unlock()
+	fake.EnableAutoMergeStub = nil
+	fake.enableAutoMergeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) EnableAutoMergeReturnsOnCall(i int, result1 error) {
+	fake.enableAutoMergeMutex.Lock()
+	defer fake.enableAutoMergeMutex.Unlock()
+	fake.EnableAutoMergeStub = nil
+	if fake.enableAutoMergeReturnsOnCall == nil {
+		fake.enableAutoMergeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.enableAutoMergeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) EnqueuePullRequest(arg1 string, arg2 string) error {
+	fake.enqueuePullRequestMutex.Lock()
+	ret, specificReturn := fake.enqueuePullRequestReturnsOnCall[len(fake.enqueuePullRequestArgsForCall)]
+	fake.enqueuePullRequestArgsForCall = append(fake.enqueuePullRequestArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.EnqueuePullRequestStub
+	fakeReturns := fake.enqueuePullRequestReturns
+	fake.recordInvocation("EnqueuePullRequest", []interface{}{arg1, arg2})
+	fake.enqueuePullRequestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) EnqueuePullRequestCallCount() int {
+	fake.enqueuePullRequestMutex.RLock()
+	defer fake.enqueuePullRequestMutex.RUnlock()
+	return len(fake.enqueuePullRequestArgsForCall)
+}
+
+func (fake *FakeProvider) EnqueuePullRequestCalls(stub func(string, string) error) {
+	fake.enqueuePullRequestMutex.Lock()
+	defer fake.enqueuePullRequestMutex.Unlock()
+	fake.EnqueuePullRequestStub = stub
+}
+
+func (fake *FakeProvider) EnqueuePullRequestArgsForCall(i int) (string, string) {
+	fake.enqueuePullRequestMutex.RLock()
+	defer fake.enqueuePullRequestMutex.RUnlock()
+	argsForCall := fake.enqueuePullRequestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) EnqueuePullRequestReturns(result1 error) {
+	fake.enqueuePullRequestMutex.Lock()
+	defer fake.enqueuePullRequestMutex.Unlock()
+	fake.EnqueuePullRequestStub = nil
+	fake.enqueuePullRequestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) EnqueuePullRequestReturnsOnCall(i int, result1 error) {
+	fake.enqueuePullRequestMutex.Lock()
+	defer fake.enqueuePullRequestMutex.Unlock()
+	fake.EnqueuePullRequestStub = nil
+	if fake.enqueuePullRequestReturnsOnCall == nil {
+		fake.enqueuePullRequestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.enqueuePullRequestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) GetChangedFiles(arg1 string, arg2 string) ([]resource.ChangedFileObject, error) {
+	fake.getChangedFilesMutex.Lock()
+	ret, specificReturn := fake.getChangedFilesReturnsOnCall[len(fake.getChangedFilesArgsForCall)]
+	fake.getChangedFilesArgsForCall = append(fake.getChangedFilesArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetChangedFilesStub
+	fakeReturns := fake.getChangedFilesReturns
+	fake.recordInvocation("GetChangedFiles", []interface{}{arg1, arg2})
+	fake.getChangedFilesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) GetChangedFilesCallCount() int {
+	fake.getChangedFilesMutex.RLock()
+	defer fake.getChangedFilesMutex.RUnlock()
+	return len(fake.getChangedFilesArgsForCall)
+}
+
+func (fake *FakeProvider) GetChangedFilesCalls(stub func(string, string) ([]resource.ChangedFileObject, error)) {
+	fake.getChangedFilesMutex.Lock()
+	defer fake.getChangedFilesMutex.Unlock()
+	fake.GetChangedFilesStub = stub
+}
+
+func (fake *FakeProvider) GetChangedFilesArgsForCall(i int) (string, string) {
+	fake.getChangedFilesMutex.RLock()
+	defer fake.getChangedFilesMutex.RUnlock()
+	argsForCall := fake.getChangedFilesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) GetChangedFilesReturns(result1 []resource.ChangedFileObject, result2 error) {
+	fake.getChangedFilesMutex.Lock()
+	defer fake.getChangedFilesMutex.Unlock()
+	fake.GetChangedFilesStub = nil
+	fake.getChangedFilesReturns = struct {
+		result1 []resource.ChangedFileObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) GetChangedFilesReturnsOnCall(i int, result1 []resource.ChangedFileObject, result2 error) {
+	fake.getChangedFilesMutex.Lock()
+	defer fake.getChangedFilesMutex.Unlock()
+	fake.GetChangedFilesStub = nil
+	if fake.getChangedFilesReturnsOnCall == nil {
+		fake.getChangedFilesReturnsOnCall = make(map[int]struct {
+			result1 []resource.ChangedFileObject
+			result2 error
+		})
+	}
+	fake.getChangedFilesReturnsOnCall[i] = struct {
+		result1 []resource.ChangedFileObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) GetComment(arg1 string, arg2 string) (*resource.CommentObject, error) {
+	fake.getCommentMutex.Lock()
+	ret, specificReturn := fake.getCommentReturnsOnCall[len(fake.getCommentArgsForCall)]
+	fake.getCommentArgsForCall = append(fake.getCommentArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetCommentStub
+	fakeReturns := fake.getCommentReturns
+	fake.recordInvocation("GetComment", []interface{}{arg1, arg2})
+	fake.getCommentMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) GetCommentCallCount() int {
+	fake.getCommentMutex.RLock()
+	defer fake.getCommentMutex.RUnlock()
+	return len(fake.getCommentArgsForCall)
+}
+
+func (fake *FakeProvider) GetCommentCalls(stub func(string, string) (*resource.CommentObject, error)) {
+	fake.getCommentMutex.Lock()
+	defer fake.getCommentMutex.Unlock()
+	fake.GetCommentStub = stub
+}
+
+func (fake *FakeProvider) GetCommentArgsForCall(i int) (string, string) {
+	fake.getCommentMutex.RLock()
+	defer fake.getCommentMutex.RUnlock()
+	argsForCall := fake.getCommentArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) GetCommentReturns(result1 *resource.CommentObject, result2 error) {
+	fake.getCommentMutex.Lock()
+	defer fake.getCommentMutex.Unlock()
+	fake.GetCommentStub = nil
+	fake.getCommentReturns = struct {
+		result1 *resource.CommentObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) GetCommentReturnsOnCall(i int, result1 *resource.CommentObject, result2 error) {
+	fake.getCommentMutex.Lock()
+	defer fake.getCommentMutex.Unlock()
+	fake.GetCommentStub = nil
+	if fake.getCommentReturnsOnCall == nil {
+		fake.getCommentReturnsOnCall = make(map[int]struct {
+			result1 *resource.CommentObject
+			result2 error
+		})
+	}
+	fake.getCommentReturnsOnCall[i] = struct {
+		result1 *resource.CommentObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) GetPullRequest(arg1 string, arg2 string) (*resource.PullRequest, error) {
+	fake.getPullRequestMutex.Lock()
+	ret, specificReturn := fake.getPullRequestReturnsOnCall[len(fake.getPullRequestArgsForCall)]
+	fake.getPullRequestArgsForCall = append(fake.getPullRequestArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetPullRequestStub
+	fakeReturns := fake.getPullRequestReturns
+	fake.recordInvocation("GetPullRequest", []interface{}{arg1, arg2})
+	fake.getPullRequestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) GetPullRequestCallCount() int {
+	fake.getPullRequestMutex.RLock()
+	defer fake.getPullRequestMutex.RUnlock()
+	return len(fake.getPullRequestArgsForCall)
+}
+
+func (fake *FakeProvider) GetPullRequestCalls(stub func(string, string) (*resource.PullRequest, error)) {
+	fake.getPullRequestMutex.Lock()
+	defer fake.getPullRequestMutex.Unlock()
+	fake.GetPullRequestStub = stub
+}
+
+func (fake *FakeProvider) GetPullRequestArgsForCall(i int) (string, string) {
+	fake.getPullRequestMutex.RLock()
+	defer fake.getPullRequestMutex.RUnlock()
+	argsForCall := fake.getPullRequestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) GetPullRequestReturns(result1 *resource.PullRequest, result2 error) {
+	fake.getPullRequestMutex.Lock()
+	defer fake.getPullRequestMutex.Unlock()
+	fake.GetPullRequestStub = nil
+	fake.getPullRequestReturns = struct {
+		result1 *resource.PullRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) GetPullRequestReturnsOnCall(i int, result1 *resource.PullRequest, result2 error) {
+	fake.getPullRequestMutex.Lock()
+	defer fake.getPullRequestMutex.Unlock()
+	fake.GetPullRequestStub = nil
+	if fake.getPullRequestReturnsOnCall == nil {
+		fake.getPullRequestReturnsOnCall = make(map[int]struct {
+			result1 *resource.PullRequest
+			result2 error
+		})
+	}
+	fake.getPullRequestReturnsOnCall[i] = struct {
+		result1 *resource.PullRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) GetPullRequestCommitBefore(arg1 int, arg2 time.Time) (string, error) {
+	fake.getPullRequestCommitBeforeMutex.Lock()
+	ret, specificReturn := fake.getPullRequestCommitBeforeReturnsOnCall[len(fake.getPullRequestCommitBeforeArgsForCall)]
+	fake.getPullRequestCommitBeforeArgsForCall = append(fake.getPullRequestCommitBeforeArgsForCall, struct {
+		arg1 int
+		arg2 time.Time
+	}{arg1, arg2})
+	stub := fake.GetPullRequestCommitBeforeStub
+	fakeReturns := fake.getPullRequestCommitBeforeReturns
+	fake.recordInvocation("GetPullRequestCommitBefore", []interface{}{arg1, arg2})
+	fake.getPullRequestCommitBeforeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) GetPullRequestCommitBeforeCallCount() int {
+	fake.getPullRequestCommitBeforeMutex.RLock()
+	defer fake.getPullRequestCommitBeforeMutex.RUnlock()
+	return len(fake.getPullRequestCommitBeforeArgsForCall)
+}
+
+func (fake *FakeProvider) GetPullRequestCommitBeforeCalls(stub func(int, time.Time) (string, error)) {
+	fake.getPullRequestCommitBeforeMutex.Lock()
+	defer fake.getPullRequestCommitBeforeMutex.Unlock()
+	fake.GetPullRequestCommitBeforeStub = stub
+}
+
+func (fake *FakeProvider) GetPullRequestCommitBeforeArgsForCall(i int) (int, time.Time) {
+	fake.getPullRequestCommitBeforeMutex.RLock()
+	defer fake.getPullRequestCommitBeforeMutex.RUnlock()
+	argsForCall := fake.getPullRequestCommitBeforeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) GetPullRequestCommitBeforeReturns(result1 string, result2 error) {
+	fake.getPullRequestCommitBeforeMutex.Lock()
+	defer fake.getPullRequestCommitBeforeMutex.Unlock()
+	fake.GetPullRequestCommitBeforeStub = nil
+	fake.getPullRequestCommitBeforeReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) GetPullRequestCommitBeforeReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getPullRequestCommitBeforeMutex.Lock()
+	defer fake.getPullRequestCommitBeforeMutex.Unlock()
+	fake.GetPullRequestCommitBeforeStub = nil
+	if fake.getPullRequestCommitBeforeReturnsOnCall == nil {
+		fake.getPullRequestCommitBeforeReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getPullRequestCommitBeforeReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListModifiedFiles(arg1 int) ([]string, error) {
+	fake.listModifiedFilesMutex.Lock()
+	ret, specificReturn := fake.listModifiedFilesReturnsOnCall[len(fake.listModifiedFilesArgsForCall)]
+	fake.listModifiedFilesArgsForCall = append(fake.listModifiedFilesArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.ListModifiedFilesStub
+	fakeReturns := fake.listModifiedFilesReturns
+	fake.recordInvocation("ListModifiedFiles", []interface{}{arg1})
+	fake.listModifiedFilesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) ListModifiedFilesCallCount() int {
+	fake.listModifiedFilesMutex.RLock()
+	defer fake.listModifiedFilesMutex.RUnlock()
+	return len(fake.listModifiedFilesArgsForCall)
+}
+
+func (fake *FakeProvider) ListModifiedFilesCalls(stub func(int) ([]string, error)) {
+	fake.listModifiedFilesMutex.Lock()
+	defer fake.listModifiedFilesMutex.Unlock()
+	fake.ListModifiedFilesStub = stub
+}
+
+func (fake *FakeProvider) ListModifiedFilesArgsForCall(i int) int {
+	fake.listModifiedFilesMutex.RLock()
+	defer fake.listModifiedFilesMutex.RUnlock()
+	argsForCall := fake.listModifiedFilesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeProvider) ListModifiedFilesReturns(result1 []string, result2 error) {
+	fake.listModifiedFilesMutex.Lock()
+	defer fake.listModifiedFilesMutex.Unlock()
+	fake.ListModifiedFilesStub = nil
+	fake.listModifiedFilesReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListModifiedFilesReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listModifiedFilesMutex.Lock()
+	defer fake.listModifiedFilesMutex.Unlock()
+	fake.ListModifiedFilesStub = nil
+	if fake.listModifiedFilesReturnsOnCall == nil {
+		fake.listModifiedFilesReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listModifiedFilesReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListModifiedFilesBetween(arg1 string, arg2 string) ([]string, error) {
+	fake.listModifiedFilesBetweenMutex.Lock()
+	ret, specificReturn := fake.listModifiedFilesBetweenReturnsOnCall[len(fake.listModifiedFilesBetweenArgsForCall)]
+	fake.listModifiedFilesBetweenArgsForCall = append(fake.listModifiedFilesBetweenArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ListModifiedFilesBetweenStub
+	fakeReturns := fake.listModifiedFilesBetweenReturns
+	fake.recordInvocation("ListModifiedFilesBetween", []interface{}{arg1, arg2})
+	fake.listModifiedFilesBetweenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) ListModifiedFilesBetweenCallCount() int {
+	fake.listModifiedFilesBetweenMutex.RLock()
+	defer fake.listModifiedFilesBetweenMutex.RUnlock()
+	return len(fake.listModifiedFilesBetweenArgsForCall)
+}
+
+func (fake *FakeProvider) ListModifiedFilesBetweenCalls(stub func(string, string) ([]string, error)) {
+	fake.listModifiedFilesBetweenMutex.Lock()
+	defer fake.listModifiedFilesBetweenMutex.Unlock()
+	fake.ListModifiedFilesBetweenStub = stub
+}
+
+func (fake *FakeProvider) ListModifiedFilesBetweenArgsForCall(i int) (string, string) {
+	fake.listModifiedFilesBetweenMutex.RLock()
+	defer fake.listModifiedFilesBetweenMutex.RUnlock()
+	argsForCall := fake.listModifiedFilesBetweenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) ListModifiedFilesBetweenReturns(result1 []string, result2 error) {
+	fake.listModifiedFilesBetweenMutex.Lock()
+	defer fake.listModifiedFilesBetweenMutex.Unlock()
+	fake.ListModifiedFilesBetweenStub = nil
+	fake.listModifiedFilesBetweenReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListModifiedFilesBetweenReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listModifiedFilesBetweenMutex.Lock()
+	defer fake.listModifiedFilesBetweenMutex.Unlock()
+	fake.ListModifiedFilesBetweenStub = nil
+	if fake.listModifiedFilesBetweenReturnsOnCall == nil {
+		fake.listModifiedFilesBetweenReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listModifiedFilesBetweenReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListOpenPullRequests() ([]*resource.PullRequest, error) {
+	fake.listOpenPullRequestsMutex.Lock()
+	ret, specificReturn := fake.listOpenPullRequestsReturnsOnCall[len(fake.listOpenPullRequestsArgsForCall)]
+	fake.listOpenPullRequestsArgsForCall = append(fake.listOpenPullRequestsArgsForCall, struct {
+	}{})
+	stub := fake.ListOpenPullRequestsStub
+	fakeReturns := fake.listOpenPullRequestsReturns
+	fake.recordInvocation("ListOpenPullRequests", []interface{}{})
+	fake.listOpenPullRequestsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) ListOpenPullRequestsCallCount() int {
+	fake.listOpenPullRequestsMutex.RLock()
+	defer fake.listOpenPullRequestsMutex.RUnlock()
+	return len(fake.listOpenPullRequestsArgsForCall)
+}
+
+func (fake *FakeProvider) ListOpenPullRequestsCalls(stub func() ([]*resource.PullRequest, error)) {
+	fake.listOpenPullRequestsMutex.Lock()
+	defer fake.listOpenPullRequestsMutex.Unlock()
+	fake.ListOpenPullRequestsStub = stub
+}
+
+func (fake *FakeProvider) ListOpenPullRequestsReturns(result1 []*resource.PullRequest, result2 error) {
+	fake.listOpenPullRequestsMutex.Lock()
+	defer fake.listOpenPullRequestsMutex.Unlock()
+	fake.ListOpenPullRequestsStub = nil
+	fake.listOpenPullRequestsReturns = struct {
+		result1 []*resource.PullRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListOpenPullRequestsReturnsOnCall(i int, result1 []*resource.PullRequest, result2 error) {
+	fake.listOpenPullRequestsMutex.Lock()
+	defer fake.listOpenPullRequestsMutex.Unlock()
+	fake.ListOpenPullRequestsStub = nil
+	if fake.listOpenPullRequestsReturnsOnCall == nil {
+		fake.listOpenPullRequestsReturnsOnCall = make(map[int]struct {
+			result1 []*resource.PullRequest
+			result2 error
+		})
+	}
+	fake.listOpenPullRequestsReturnsOnCall[i] = struct {
+		result1 []*resource.PullRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListPullRequestCommits(arg1 int, arg2 string) ([]resource.CommitObject, error) {
+	fake.listPullRequestCommitsMutex.Lock()
+	ret, specificReturn := fake.listPullRequestCommitsReturnsOnCall[len(fake.listPullRequestCommitsArgsForCall)]
+	fake.listPullRequestCommitsArgsForCall = append(fake.listPullRequestCommitsArgsForCall, struct {
+		arg1 int
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ListPullRequestCommitsStub
+	fakeReturns := fake.listPullRequestCommitsReturns
+	fake.recordInvocation("ListPullRequestCommits", []interface{}{arg1, arg2})
+	fake.listPullRequestCommitsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) ListPullRequestCommitsCallCount() int {
+	fake.listPullRequestCommitsMutex.RLock()
+	defer fake.listPullRequestCommitsMutex.RUnlock()
+	return len(fake.listPullRequestCommitsArgsForCall)
+}
+
+func (fake *FakeProvider) ListPullRequestCommitsCalls(stub func(int, string) ([]resource.CommitObject, error)) {
+	fake.listPullRequestCommitsMutex.Lock()
+	defer fake.listPullRequestCommitsMutex.Unlock()
+	fake.ListPullRequestCommitsStub = stub
+}
+
+func (fake *FakeProvider) ListPullRequestCommitsArgsForCall(i int) (int, string) {
+	fake.listPullRequestCommitsMutex.RLock()
+	defer fake.listPullRequestCommitsMutex.RUnlock()
+	argsForCall := fake.listPullRequestCommitsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) ListPullRequestCommitsReturns(result1 []resource.CommitObject, result2 error) {
+	fake.listPullRequestCommitsMutex.Lock()
+	defer fake.listPullRequestCommitsMutex.Unlock()
+	fake.ListPullRequestCommitsStub = nil
+	fake.listPullRequestCommitsReturns = struct {
+		result1 []resource.CommitObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListPullRequestCommitsReturnsOnCall(i int, result1 []resource.CommitObject, result2 error) {
+	fake.listPullRequestCommitsMutex.Lock()
+	defer fake.listPullRequestCommitsMutex.Unlock()
+	fake.ListPullRequestCommitsStub = nil
+	if fake.listPullRequestCommitsReturnsOnCall == nil {
+		fake.listPullRequestCommitsReturnsOnCall = make(map[int]struct {
+			result1 []resource.CommitObject
+			result2 error
+		})
+	}
+	fake.listPullRequestCommitsReturnsOnCall[i] = struct {
+		result1 []resource.CommitObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListTeamMembers(arg1 string, arg2 string) ([]string, error) {
+	fake.listTeamMembersMutex.Lock()
+	ret, specificReturn := fake.listTeamMembersReturnsOnCall[len(fake.listTeamMembersArgsForCall)]
+	fake.listTeamMembersArgsForCall = append(fake.listTeamMembersArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ListTeamMembersStub
+	fakeReturns := fake.listTeamMembersReturns
+	fake.recordInvocation("ListTeamMembers", []interface{}{arg1, arg2})
+	fake.listTeamMembersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) ListTeamMembersCallCount() int {
+	fake.listTeamMembersMutex.RLock()
+	defer fake.listTeamMembersMutex.RUnlock()
+	return len(fake.listTeamMembersArgsForCall)
+}
+
+func (fake *FakeProvider) ListTeamMembersCalls(stub func(string, string) ([]string, error)) {
+	fake.listTeamMembersMutex.Lock()
+	defer fake.listTeamMembersMutex.Unlock()
+	fake.ListTeamMembersStub = stub
+}
+
+func (fake *FakeProvider) ListTeamMembersArgsForCall(i int) (string, string) {
+	fake.listTeamMembersMutex.RLock()
+	defer fake.listTeamMembersMutex.RUnlock()
+	argsForCall := fake.listTeamMembersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) ListTeamMembersReturns(result1 []string, result2 error) {
+	fake.listTeamMembersMutex.Lock()
+	defer fake.listTeamMembersMutex.Unlock()
+	fake.ListTeamMembersStub = nil
+	fake.listTeamMembersReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListTeamMembersReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listTeamMembersMutex.Lock()
+	defer fake.listTeamMembersMutex.Unlock()
+	fake.ListTeamMembersStub = nil
+	if fake.listTeamMembersReturnsOnCall == nil {
+		fake.listTeamMembersReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listTeamMembersReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) MergePullRequest(arg1 string, arg2 string, arg3 resource.Merge) (string, error) {
+	fake.mergePullRequestMutex.Lock()
+	ret, specificReturn := fake.mergePullRequestReturnsOnCall[len(fake.mergePullRequestArgsForCall)]
+	fake.mergePullRequestArgsForCall = append(fake.mergePullRequestArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 resource.Merge
+	}{arg1, arg2, arg3})
+	stub := fake.MergePullRequestStub
+	fakeReturns := fake.mergePullRequestReturns
+	fake.recordInvocation("MergePullRequest", []interface{}{arg1, arg2, arg3})
+	fake.mergePullRequestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) MergePullRequestCallCount() int {
+	fake.mergePullRequestMutex.RLock()
+	defer fake.mergePullRequestMutex.RUnlock()
+	return len(fake.mergePullRequestArgsForCall)
+}
+
+func (fake *FakeProvider) MergePullRequestCalls(stub func(string, string, resource.Merge) (string, error)) {
+	fake.mergePullRequestMutex.Lock()
+	defer fake.mergePullRequestMutex.Unlock()
+	fake.MergePullRequestStub = stub
+}
+
+func (fake *FakeProvider) MergePullRequestArgsForCall(i int) (string, string, resource.Merge) {
+	fake.mergePullRequestMutex.RLock()
+	defer fake.mergePullRequestMutex.RUnlock()
+	argsForCall := fake.mergePullRequestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProvider) MergePullRequestReturns(result1 string, result2 error) {
+	fake.mergePullRequestMutex.Lock()
+	defer fake.mergePullRequestMutex.Unlock()
+	fake.MergePullRequestStub = nil
+	fake.mergePullRequestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) MergePullRequestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.mergePullRequestMutex.Lock()
+	defer fake.mergePullRequestMutex.Unlock()
+	fake.MergePullRequestStub = nil
+	if fake.mergePullRequestReturnsOnCall == nil {
+		fake.mergePullRequestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.mergePullRequestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) PostComment(arg1 string, arg2 string) error {
+	fake.postCommentMutex.Lock()
+	ret, specificReturn := fake.postCommentReturnsOnCall[len(fake.postCommentArgsForCall)]
+	fake.postCommentArgsForCall = append(fake.postCommentArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.PostCommentStub
+	fakeReturns := fake.postCommentReturns
+	fake.recordInvocation("PostComment", []interface{}{arg1, arg2})
+	fake.postCommentMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) PostCommentCallCount() int {
+	fake.postCommentMutex.RLock()
+	defer fake.postCommentMutex.RUnlock()
+	return len(fake.postCommentArgsForCall)
+}
+
+func (fake *FakeProvider) PostCommentCalls(stub func(string, string) error) {
+	fake.postCommentMutex.Lock()
+	defer fake.postCommentMutex.Unlock()
+	fake.PostCommentStub = stub
+}
+
+func (fake *FakeProvider) PostCommentArgsForCall(i int) (string, string) {
+	fake.postCommentMutex.RLock()
+	defer fake.postCommentMutex.RUnlock()
+	argsForCall := fake.postCommentArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) PostCommentReturns(result1 error) {
+	fake.postCommentMutex.Lock()
+	defer fake.postCommentMutex.Unlock()
+	fake.PostCommentStub = nil
+	fake.postCommentReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) PostCommentReturnsOnCall(i int, result1 error) {
+	fake.postCommentMutex.Lock()
+	defer fake.postCommentMutex.Unlock()
+	fake.PostCommentStub = nil
+	if fake.postCommentReturnsOnCall == nil {
+		fake.postCommentReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.postCommentReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) RemoveLabels(arg1 string, arg2 []string) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.removeLabelsMutex.Lock()
+	ret, specificReturn := fake.removeLabelsReturnsOnCall[len(fake.removeLabelsArgsForCall)]
+	fake.removeLabelsArgsForCall = append(fake.removeLabelsArgsForCall, struct {
+		arg1 string
+		arg2 []string
+	}{arg1, arg2Copy})
+	stub := fake.RemoveLabelsStub
+	fakeReturns := fake.removeLabelsReturns
+	fake.recordInvocation("RemoveLabels", []interface{}{arg1, arg2Copy})
+	fake.removeLabelsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) RemoveLabelsCallCount() int {
+	fake.removeLabelsMutex.RLock()
+	defer fake.removeLabelsMutex.RUnlock()
+	return len(fake.removeLabelsArgsForCall)
+}
+
+func (fake *FakeProvider) RemoveLabelsCalls(stub func(string, []string) error) {
+	fake.removeLabelsMutex.Lock()
+	defer fake.removeLabelsMutex.Unlock()
+	fake.RemoveLabelsStub = stub
+}
+
+func (fake *FakeProvider) RemoveLabelsArgsForCall(i int) (string, []string) {
+	fake.removeLabelsMutex.RLock()
+	defer fake.removeLabelsMutex.RUnlock()
+	argsForCall := fake.removeLabelsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) RemoveLabelsReturns(result1 error) {
+	fake.removeLabelsMutex.Lock()
+	defer fake.removeLabelsMutex.Unlock()
+	fake.RemoveLabelsStub = nil
+	fake.removeLabelsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) RemoveLabelsReturnsOnCall(i int, result1 error) {
+	fake.removeLabelsMutex.Lock()
+	defer fake.removeLabelsMutex.Unlock()
+	fake.RemoveLabelsStub = nil
+	if fake.removeLabelsReturnsOnCall == nil {
+		fake.removeLabelsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeLabelsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) UpdateCheckRun(arg1 string, arg2 resource.CheckRun) error {
+	fake.updateCheckRunMutex.Lock()
+	ret, specificReturn := fake.updateCheckRunReturnsOnCall[len(fake.updateCheckRunArgsForCall)]
+	fake.updateCheckRunArgsForCall = append(fake.updateCheckRunArgsForCall, struct {
+		arg1 string
+		arg2 resource.CheckRun
+	}{arg1, arg2})
+	stub := fake.UpdateCheckRunStub
+	fakeReturns := fake.updateCheckRunReturns
+	fake.recordInvocation("UpdateCheckRun", []interface{}{arg1, arg2})
+	fake.updateCheckRunMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) UpdateCheckRunCallCount() int {
+	fake.updateCheckRunMutex.RLock()
+	defer fake.updateCheckRunMutex.RUnlock()
+	return len(fake.updateCheckRunArgsForCall)
+}
+
+func (fake *FakeProvider) UpdateCheckRunCalls(stub func(string, resource.CheckRun) error) {
+	fake.updateCheckRunMutex.Lock()
+	defer fake.updateCheckRunMutex.Unlock()
+	fake.UpdateCheckRunStub = stub
+}
+
+func (fake *FakeProvider) UpdateCheckRunArgsForCall(i int) (string, resource.CheckRun) {
+	fake.updateCheckRunMutex.RLock()
+	defer fake.updateCheckRunMutex.RUnlock()
+	argsForCall := fake.updateCheckRunArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) UpdateCheckRunReturns(result1 error) {
+	fake.updateCheckRunMutex.Lock()
+	defer fake.updateCheckRunMutex.Unlock()
+	fake.UpdateCheckRunStub = nil
+	fake.updateCheckRunReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) UpdateCheckRunReturnsOnCall(i int, result1 error) {
+	fake.updateCheckRunMutex.Lock()
+	defer fake.updateCheckRunMutex.Unlock()
+	fake.UpdateCheckRunStub = nil
+	if fake.updateCheckRunReturnsOnCall == nil {
+		fake.updateCheckRunReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateCheckRunReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) UpdateComment(arg1 string, arg2 string, arg3 string) error {
+	fake.updateCommentMutex.Lock()
+	ret, specificReturn := fake.updateCommentReturnsOnCall[len(fake.updateCommentArgsForCall)]
+	fake.updateCommentArgsForCall = append(fake.updateCommentArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateCommentStub
+	fakeReturns := fake.updateCommentReturns
+	fake.recordInvocation("UpdateComment", []interface{}{arg1, arg2, arg3})
+	fake.updateCommentMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) UpdateCommentCallCount() int {
+	fake.updateCommentMutex.RLock()
+	defer fake.updateCommentMutex.RUnlock()
+	return len(fake.updateCommentArgsForCall)
+}
+
+func (fake *FakeProvider) UpdateCommentCalls(stub func(string, string, string) error) {
+	fake.updateCommentMutex.Lock()
+	defer fake.updateCommentMutex.Unlock()
+	fake.UpdateCommentStub = stub
+}
+
+func (fake *FakeProvider) UpdateCommentArgsForCall(i int) (string, string, string) {
+	fake.updateCommentMutex.RLock()
+	defer fake.updateCommentMutex.RUnlock()
+	argsForCall := fake.updateCommentArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProvider) UpdateCommentReturns(result1 error) {
+	fake.updateCommentMutex.Lock()
+	defer fake.updateCommentMutex.Unlock()
+	fake.UpdateCommentStub = nil
+	fake.updateCommentReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) UpdateCommentReturnsOnCall(i int, result1 error) {
+	fake.updateCommentMutex.Lock()
+	defer fake.updateCommentMutex.Unlock()
+	fake.UpdateCommentStub = nil
+	if fake.updateCommentReturnsOnCall == nil {
+		fake.updateCommentReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateCommentReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) UpdateCommitStatus(arg1 string, arg2 string, arg3 string, arg4 string, arg5 string, arg6 string) error {
+	fake.updateCommitStatusMutex.Lock()
+	ret, specificReturn := fake.updateCommitStatusReturnsOnCall[len(fake.updateCommitStatusArgsForCall)]
+	fake.updateCommitStatusArgsForCall = append(fake.updateCommitStatusArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+		arg6 string
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.UpdateCommitStatusStub
+	fakeReturns := fake.updateCommitStatusReturns
+	fake.recordInvocation("UpdateCommitStatus", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.updateCommitStatusMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) UpdateCommitStatusCallCount() int {
+	fake.updateCommitStatusMutex.RLock()
+	defer fake.updateCommitStatusMutex.RUnlock()
+	return len(fake.updateCommitStatusArgsForCall)
+}
+
+func (fake *FakeProvider) UpdateCommitStatusCalls(stub func(string, string, string, string, string, string) error) {
+	fake.updateCommitStatusMutex.Lock()
+	defer fake.updateCommitStatusMutex.Unlock()
+	fake.UpdateCommitStatusStub = stub
+}
+
+func (fake *FakeProvider) UpdateCommitStatusArgsForCall(i int) (string, string, string, string, string, string) {
+	fake.updateCommitStatusMutex.RLock()
+	defer fake.updateCommitStatusMutex.RUnlock()
+	argsForCall := fake.updateCommitStatusArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+}
+
+func (fake *FakeProvider) UpdateCommitStatusReturns(result1 error) {
+	fake.updateCommitStatusMutex.Lock()
+	defer fake.updateCommitStatusMutex.Unlock()
+	fake.UpdateCommitStatusStub = nil
+	fake.updateCommitStatusReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) UpdateCommitStatusReturnsOnCall(i int, result1 error) {
+	fake.updateCommitStatusMutex.Lock()
+	defer fake.updateCommitStatusMutex.Unlock()
+	fake.UpdateCommitStatusStub = nil
+	if fake.updateCommitStatusReturnsOnCall == nil {
+		fake.updateCommitStatusReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateCommitStatusReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ resource.Provider = new(FakeProvider)
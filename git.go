@@ -11,6 +11,8 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+
+	"golang.org/x/oauth2"
 )
 
 // Git interface for testing purposes.
@@ -20,29 +22,49 @@ type Git interface {
 	Pull(string, string, int) error
 	RevParse(string) (string, error)
 	Fetch(string, int, int) error
+	FetchRef(string, string, int) error
 	Checkout(string, string) error
 	Merge(string) error
 	Rebase(string, string) error
 	GitCryptUnlock(string) error
 }
 
-// NewGitClient ...
-func NewGitClient(source *Source, dir string, output io.Writer) (*GitClient, error) {
+// NewGitClient creates a git client, which uses a token source for the source
+// configuration if tokenSource is nil.
+func NewGitClient(source *Source, tokenSource oauth2.TokenSource, dir string, output io.Writer) (*GitClient, error) {
 	if source.SkipSSLVerification {
 		os.Setenv("GIT_SSL_NO_VERIFY", "true")
 	}
+	if tokenSource == nil {
+		var err error
+		if tokenSource, err = NewTokenSource(source, nil); err != nil {
+			return nil, err
+		}
+	}
+	username, pullRequestRef := "x-oauth-basic", "pull/%d/head"
+	if source.GithubAppID != 0 {
+		username = "x-access-token"
+	}
+	if source.ProviderName() == "gitlab" {
+		username, pullRequestRef = "oauth2", "merge-requests/%d/head"
+	}
 	return &GitClient{
-		AccessToken: source.AccessToken,
-		Directory:   dir,
-		Output:      output,
+		TokenSource:    tokenSource,
+		Username:       username,
+		PullRequestRef: pullRequestRef,
+		Directory:      dir,
+		Output:         output,
 	}, nil
 }
 
 // GitClient ...
 type GitClient struct {
-	AccessToken string
-	Directory   string
-	Output      io.Writer
+	TokenSource oauth2.TokenSource
+	Username    string
+	// PullRequestRef is the format of the ref for the head of a pull request (defaults to pull/%d/head).
+	PullRequestRef string
+	Directory      string
+	Output         io.Writer
 }
 
 func (g *GitClient) command(name string, arg ...string) *exec.Cmd {
@@ -111,7 +133,35 @@ func (g *GitClient) Fetch(uri string, prNumber int, depth int) error {
 		return err
 	}
 
-	args := []string{"fetch", endpoint, fmt.Sprintf("pull/%s/head", strconv.Itoa(prNumber))}
+	ref := g.PullRequestRef
+	if ref == "" {
+		ref = "pull/%d/head"
+	}
+
+	args := []string{"fetch", endpoint, fmt.Sprintf(ref, prNumber)}
+	if depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(depth))
+	}
+	cmd := g.command("git", args...)
+
+	// Discard output to have zero chance of logging the access token.
+	cmd.Stdout = ioutil.Discard
+	cmd.Stderr = ioutil.Discard
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("fetch failed: %s", err)
+	}
+	return nil
+}
+
+// FetchRef fetches a ref or commit SHA (e.g. the commit of a merge group).
+func (g *GitClient) FetchRef(uri string, ref string, depth int) error {
+	endpoint, err := g.Endpoint(uri)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"fetch", endpoint, ref}
 	if depth > 0 {
 		args = append(args, "--depth", strconv.Itoa(depth))
 	}
@@ -139,6 +189,9 @@ func (g *GitClient) Checkout(branch, sha string) error {
 // Merge ...
 func (g *GitClient) Merge(sha string) error {
 	if err := g.command("git", "merge", sha, "--no-stat").Run(); err != nil {
+		if conflict := g.abortConflict("merge"); conflict != nil {
+			return conflict
+		}
 		return fmt.Errorf("merge failed: %s", err)
 	}
 	return nil
@@ -147,11 +200,45 @@ func (g *GitClient) Merge(sha string) error {
 // Rebase ...
 func (g *GitClient) Rebase(baseRef string, headSha string) error {
 	if err := g.command("git", "rebase", baseRef, headSha).Run(); err != nil {
+		if conflict := g.abortConflict("rebase"); conflict != nil {
+			return conflict
+		}
 		return fmt.Errorf("rebase failed: %s", err)
 	}
 	return nil
 }
 
+// MergeConflictError is returned when a merge or rebase fails due to conflicts.
+type MergeConflictError struct {
+	Files []string
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("merge conflict in: %s", strings.Join(e.Files, ", "))
+}
+
+// abortConflict aborts the merge or rebase in progress and returns the conflicting
+// files, or nil if the operation did not fail due to conflicts.
+func (g *GitClient) abortConflict(operation string) *MergeConflictError {
+	cmd := exec.Command("git", "diff", "--name-only", "--diff-filter=U")
+	cmd.Dir = g.Directory
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, f := range strings.Split(string(out), "\n") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	g.command("git", operation, "--abort").Run()
+	return &MergeConflictError{Files: files}
+}
+
 // GitCryptUnlock unlocks the repository using git-crypt
 func (g *GitClient) GitCryptUnlock(base64key string) error {
 	keyDir, err := ioutil.TempDir("", "")
@@ -179,6 +266,10 @@ func (g *GitClient) Endpoint(uri string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to parse commit url: %s", err)
 	}
-	endpoint.User = url.UserPassword("x-oauth-basic", g.AccessToken)
+	token, err := g.TokenSource.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token: %s", err)
+	}
+	endpoint.User = url.UserPassword(g.Username, token.AccessToken)
 	return endpoint.String(), nil
 }
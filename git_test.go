@@ -0,0 +1,101 @@
+package resource_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestGitClientMergeConflict(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := createTestDirectory(t)
+	defer os.RemoveAll(dir)
+
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	write := func(name, content string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	run("init")
+	run("config", "user.name", "concourse-ci")
+	run("config", "user.email", "concourse@local")
+	run("checkout", "-b", "master")
+	write("README.md", "base\n")
+	write("main.go", "package main\n")
+	run("add", "-A")
+	run("commit", "-m", "base")
+
+	run("checkout", "-b", "feature")
+	write("README.md", "feature\n")
+	write("main.go", "package feature\n")
+	run("commit", "-am", "feature")
+	head := run("rev-parse", "HEAD")
+
+	run("checkout", "master")
+	write("README.md", "master\n")
+	write("main.go", "package master\n")
+	run("commit", "-am", "master")
+
+	git := &resource.GitClient{Directory: dir, Output: ioutil.Discard}
+	err := git.Merge(head)
+	if assert.Error(t, err) {
+		assert.Equal(t, &resource.MergeConflictError{Files: []string{"README.md", "main.go"}}, err)
+		assert.Equal(t, "merge conflict in: README.md, main.go", err.Error())
+	}
+
+	// The merge should be aborted.
+	assert.Equal(t, "", run("status", "--porcelain"))
+}
+
+func TestNewGitClient(t *testing.T) {
+	tests := []struct {
+		description    string
+		source         resource.Source
+		username       string
+		pullRequestRef string
+	}{
+		{
+			description:    "uses the pull request refs of github",
+			source:         resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+			username:       "x-oauth-basic",
+			pullRequestRef: "pull/%d/head",
+		},
+		{
+			description:    "uses the merge request refs of gitlab",
+			source:         resource.Source{Provider: "gitlab", Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+			username:       "oauth2",
+			pullRequestRef: "merge-requests/%d/head",
+		},
+		{
+			description:    "uses the pull request refs of gitea",
+			source:         resource.Source{Provider: "gitea", Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", Endpoint: "https://gitea.example.com/api/v1"},
+			username:       "x-oauth-basic",
+			pullRequestRef: "pull/%d/head",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			git, err := resource.NewGitClient(&tc.source, nil, "", ioutil.Discard)
+			require.NoError(t, err)
+			assert.Equal(t, tc.username, git.Username)
+			assert.Equal(t, tc.pullRequestRef, git.PullRequestRef)
+		})
+	}
+}
@@ -0,0 +1,802 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/oauth2"
+)
+
+// giteaPageSize is the default maximum number of items per page in Gitea.
+const giteaPageSize = 50
+
+// GiteaClient for handling requests to the Gitea (V1) API, which is also used by Forgejo.
+type GiteaClient struct {
+	Repository string
+	Owner      string
+	api        *restClient
+	source     Source
+}
+
+// NewGiteaClient creates a Gitea client, which uses a token source for the
+// source configuration if tokenSource is nil.
+func NewGiteaClient(s *Source, tokenSource oauth2.TokenSource) (*GiteaClient, error) {
+	owner, repository, err := parseRepository(s.Repository)
+	if err != nil {
+		return nil, err
+	}
+	api, err := newRESTClient(s, tokenSource, s.Endpoint, "limit", giteaPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &GiteaClient{
+		Owner:      owner,
+		Repository: repository,
+		api:        api,
+		source:     *s,
+	}, nil
+}
+
+// giteaPullRequest represents a pull request.
+// https://try.gitea.io/api/swagger#/repository/repoGetPullRequest
+type giteaPullRequest struct {
+	ID              int64        `json:"id"`
+	Number          int          `json:"number"`
+	Title           string       `json:"title"`
+	State           string       `json:"state"`
+	HTMLURL         string       `json:"html_url"`
+	Draft           bool         `json:"draft"`
+	Mergeable       bool         `json:"mergeable"`
+	ConflictedFiles []string     `json:"conflicted_files"`
+	Merged          bool         `json:"merged"`
+	MergeCommitSHA  string       `json:"merge_commit_sha"`
+	User            giteaUser    `json:"user"`
+	Labels          []giteaLabel `json:"labels"`
+	Head            giteaBranch  `json:"head"`
+	Base            giteaBranch  `json:"base"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// giteaBranch represents the head or base of a pull request.
+type giteaBranch struct {
+	Ref    string `json:"ref"`
+	SHA    string `json:"sha"`
+	RepoID int64  `json:"repo_id"`
+	Repo   struct {
+		HTMLURL string `json:"html_url"`
+	} `json:"repo"`
+}
+
+// giteaUser represents a user (e.g. the author of a pull request or comment).
+type giteaUser struct {
+	Login string `json:"login"`
+}
+
+// giteaLabel represents a label in a repository.
+type giteaLabel struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// giteaCommit represents a commit, as returned by the commit and pull request commit APIs.
+type giteaCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Message   string `json:"message"`
+		Committer struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+	Author *giteaUser `json:"author"`
+	Files  []struct {
+		Filename string `json:"filename"`
+	} `json:"files"`
+}
+
+// giteaComment represents a comment on a pull request (issue).
+type giteaComment struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	User      giteaUser `json:"user"`
+}
+
+// ListOpenPullRequests gets the last commit on all open pull requests. Details that are only
+// used by some of the source configuration (e.g. approvals) are requested when needed.
+func (m *GiteaClient) ListOpenPullRequests() ([]*PullRequest, error) {
+	var pulls []giteaPullRequest
+	var page []giteaPullRequest
+	err := m.api.list(m.repoPath("pulls"), url.Values{"state": {"open"}}, &page, func() bool {
+		pulls = append(pulls, page...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Comments are authorised if their author is a collaborator on the repository.
+	var collaborators map[string]bool
+	if m.source.TriggerPhrase != "" {
+		collaborators = map[string]bool{m.Owner: true}
+		var page []giteaUser
+		err := m.api.list(m.repoPath("collaborators"), nil, &page, func() bool {
+			for _, u := range page {
+				collaborators[u.Login] = true
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list collaborators: %s", err)
+		}
+	}
+
+	// The tips of the base branches are shared by the pull requests against them.
+	baseTips := make(map[string]CommitObject)
+	if m.source.TrackBaseBranch {
+		for _, pr := range pulls {
+			if _, ok := baseTips[pr.Base.Ref]; ok {
+				continue
+			}
+			var branch struct {
+				Commit struct {
+					ID        string    `json:"id"`
+					Message   string    `json:"message"`
+					Timestamp time.Time `json:"timestamp"`
+				} `json:"commit"`
+			}
+			if _, err := m.api.do(http.MethodGet, m.repoPath("branches", pr.Base.Ref), nil, nil, &branch); err != nil {
+				return nil, fmt.Errorf("failed to get base branch %s: %s", pr.Base.Ref, err)
+			}
+			baseTips[pr.Base.Ref] = CommitObject{
+				ID:            branch.Commit.ID,
+				OID:           branch.Commit.ID,
+				CommittedDate: githubv4.DateTime{Time: branch.Commit.Timestamp},
+				Message:       branch.Commit.Message,
+			}
+		}
+	}
+
+	// Each pull request takes at least one more request, so they are made concurrently if
+	// specified by check_concurrency.
+	response := make([]*PullRequest, len(pulls))
+	errs := runConcurrently(len(pulls), m.source.CheckConcurrency, func(i int) error {
+		p, err := m.openPullRequest(pulls[i], collaborators, baseTips)
+		response[i] = p
+		return err
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// openPullRequest returns an open pull request with the tip, and the details that are used
+// by the source configuration.
+func (m *GiteaClient) openPullRequest(pr giteaPullRequest, collaborators map[string]bool, baseTips map[string]CommitObject) (*PullRequest, error) {
+	number := strconv.Itoa(pr.Number)
+	p := &PullRequest{PullRequestObject: pr.pullRequestObject()}
+
+	var commit giteaCommit
+	if _, err := m.api.do(http.MethodGet, m.repoPath("git", "commits", pr.Head.SHA), url.Values{"stat": {"false"}, "files": {"false"}}, nil, &commit); err != nil {
+		return nil, fmt.Errorf("failed to get tip of pull request %s: %s", number, err)
+	}
+	p.Tip = commit.commitObject()
+
+	for _, l := range pr.Labels {
+		p.Labels = append(p.Labels, LabelObject{Name: l.Name})
+	}
+
+	if m.source.RequiredReviewApprovals > 0 {
+		var page []struct {
+			State     string `json:"state"`
+			Stale     bool   `json:"stale"`
+			Dismissed bool   `json:"dismissed"`
+		}
+		err := m.api.list(m.repoPath("pulls", number, "reviews"), nil, &page, func() bool {
+			for _, r := range page {
+				if r.State == "APPROVED" && !r.Stale && !r.Dismissed {
+					p.ApprovedReviewCount++
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list reviews of pull request %s: %s", number, err)
+		}
+	}
+
+	if m.source.TriggerPhrase != "" {
+		var page []giteaComment
+		err := m.api.list(m.repoPath("issues", number, "comments"), nil, &page, func() bool {
+			for _, n := range page {
+				c := n.commentObject()
+				if collaborators[c.Author.Login] {
+					c.AuthorAssociation = CommentAuthorAssociationCollaborator
+				}
+				p.Comments = append(p.Comments, c)
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comments of pull request %s: %s", number, err)
+		}
+	}
+
+	// Label events are part of the timeline, where the body is "1" when a label was added.
+	if len(m.source.Labels) > 0 || len(m.source.IgnoreLabels) > 0 {
+		var page []struct {
+			Type      string     `json:"type"`
+			Body      string     `json:"body"`
+			CreatedAt time.Time  `json:"created_at"`
+			Label     giteaLabel `json:"label"`
+		}
+		err := m.api.list(m.repoPath("issues", number, "timeline"), nil, &page, func() bool {
+			for _, e := range page {
+				if e.Type == "label" {
+					p.LabelEvents = append(p.LabelEvents, LabelEvent{Name: e.Label.Name, Added: e.Body == "1", CreatedAt: e.CreatedAt})
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list label events of pull request %s: %s", number, err)
+		}
+	}
+
+	if len(m.source.RequiredStatuses) > 0 {
+		var combined struct {
+			Statuses []struct {
+				Context   string    `json:"context"`
+				Status    string    `json:"status"`
+				UpdatedAt time.Time `json:"updated_at"`
+			} `json:"statuses"`
+		}
+		if _, err := m.api.do(http.MethodGet, m.repoPath("commits", pr.Head.SHA, "status"), nil, nil, &combined); err != nil {
+			return nil, fmt.Errorf("failed to get statuses of pull request %s: %s", number, err)
+		}
+		for _, s := range combined.Statuses {
+			p.Statuses = append(p.Statuses, Status{Context: s.Context, State: s.Status, UpdatedAt: s.UpdatedAt})
+		}
+	}
+
+	if m.source.TrackBaseBranch {
+		p.BaseTip = baseTips[pr.Base.Ref]
+	}
+	return p, nil
+}
+
+// ListModifiedFiles in a pull request.
+func (m *GiteaClient) ListModifiedFiles(prNumber int) ([]string, error) {
+	var files []string
+	var page []struct {
+		Filename string `json:"filename"`
+	}
+	err := m.api.list(m.repoPath("pulls", strconv.Itoa(prNumber), "files"), nil, &page, func() bool {
+		for _, f := range page {
+			files = append(files, f.Filename)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// ListModifiedFilesBetween two commits, using the files of the commits in the comparison.
+func (m *GiteaClient) ListModifiedFilesBetween(base, head string) ([]string, error) {
+	var comparison struct {
+		Commits []giteaCommit `json:"commits"`
+	}
+	if _, err := m.api.do(http.MethodGet, m.repoPath("compare", base+"..."+head), nil, nil, &comparison); err != nil {
+		return nil, err
+	}
+	var files []string
+	seen := make(map[string]bool)
+	for _, c := range comparison.Commits {
+		for _, f := range c.Files {
+			if !seen[f.Filename] {
+				seen[f.Filename] = true
+				files = append(files, f.Filename)
+			}
+		}
+	}
+	return files, nil
+}
+
+// ListPullRequestCommits returns the commits in a pull request after the given commit, oldest
+// first. All commits are returned if the given commit is not part of the pull request (e.g.
+// after a force push).
+func (m *GiteaClient) ListPullRequestCommits(prNumber int, after string) ([]CommitObject, error) {
+	var response []CommitObject
+	err := m.walkPullRequestCommits(prNumber, func(c CommitObject) bool {
+		if c.OID == after {
+			return false
+		}
+		response = append([]CommitObject{c}, response...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetPullRequestCommitBefore returns the SHA of the latest commit in a pull request that was
+// committed at or before the given time, or an empty string if there is none.
+func (m *GiteaClient) GetPullRequestCommitBefore(prNumber int, before time.Time) (string, error) {
+	var sha string
+	err := m.walkPullRequestCommits(prNumber, func(c CommitObject) bool {
+		if c.CommittedDate.Time.After(before) {
+			return true
+		}
+		sha = c.OID
+		return false
+	})
+	if err != nil {
+		return "", err
+	}
+	return sha, nil
+}
+
+// walkPullRequestCommits calls fn for the commits in a pull request, from the tip and
+// backwards, until fn returns false.
+func (m *GiteaClient) walkPullRequestCommits(prNumber int, fn func(CommitObject) bool) error {
+	var page []giteaCommit
+	query := url.Values{"stat": {"false"}, "files": {"false"}, "verification": {"false"}}
+	return m.api.list(m.repoPath("pulls", strconv.Itoa(prNumber), "commits"), query, &page, func() bool {
+		for _, c := range page {
+			if !fn(c.commitObject()) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// PostComment to a pull request.
+func (m *GiteaClient) PostComment(prNumber, comment string) error {
+	body := map[string]string{"body": comment}
+	_, err := m.api.do(http.MethodPost, m.repoPath("issues", prNumber, "comments"), nil, body, nil)
+	return err
+}
+
+// UpdateComment on a pull request, identified by a hidden marker containing the key. The
+// comment is created if the user has not posted a comment with the same key before.
+func (m *GiteaClient) UpdateComment(prNumber, key, comment string) error {
+	marker := commentMarker(key)
+	body := map[string]string{"body": comment + "\n\n" + marker}
+
+	var id int64
+	err := m.walkOwnComments(prNumber, func(c giteaComment) {
+		if strings.Contains(c.Body, marker) {
+			id = c.ID
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if id == 0 {
+		_, err = m.api.do(http.MethodPost, m.repoPath("issues", prNumber, "comments"), nil, body, nil)
+		return err
+	}
+	_, err = m.api.do(http.MethodPatch, m.repoPath("issues", "comments", strconv.FormatInt(id, 10)), nil, body, nil)
+	return err
+}
+
+// DeletePreviousComments posted to a pull request by the user.
+func (m *GiteaClient) DeletePreviousComments(prNumber string) error {
+	var ids []int64
+	err := m.walkOwnComments(prNumber, func(c giteaComment) {
+		ids = append(ids, c.ID)
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, err := m.api.do(http.MethodDelete, m.repoPath("issues", "comments", strconv.FormatInt(id, 10)), nil, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkOwnComments calls fn for the comments on a pull request that were posted by the
+// authenticated user, oldest first.
+func (m *GiteaClient) walkOwnComments(prNumber string, fn func(giteaComment)) error {
+	var user giteaUser
+	if _, err := m.api.do(http.MethodGet, "user", nil, nil, &user); err != nil {
+		return err
+	}
+
+	var page []giteaComment
+	return m.api.list(m.repoPath("issues", prNumber, "comments"), nil, &page, func() bool {
+		for _, c := range page {
+			if c.User.Login == user.Login {
+				fn(c)
+			}
+		}
+		return true
+	})
+}
+
+// GetPullRequest returns the pull request with the given commit as its tip.
+func (m *GiteaClient) GetPullRequest(prNumber, commitRef string) (*PullRequest, error) {
+	var pr giteaPullRequest
+	if _, err := m.api.do(http.MethodGet, m.repoPath("pulls", prNumber), nil, nil, &pr); err != nil {
+		return nil, err
+	}
+
+	var tip *CommitObject
+	err := m.walkPullRequestCommits(pr.Number, func(c CommitObject) bool {
+		if c.OID == commitRef {
+			tip = &c
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tip == nil {
+		return nil, fmt.Errorf("commit with ref '%s' does not exist", commitRef)
+	}
+	return &PullRequest{
+		PullRequestObject: pr.pullRequestObject(),
+		Tip:               *tip,
+	}, nil
+}
+
+// GetChangedFiles in a pull request.
+func (m *GiteaClient) GetChangedFiles(prNumber string, commitRef string) ([]ChangedFileObject, error) {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+	files, err := m.ListModifiedFiles(pr)
+	if err != nil {
+		return nil, err
+	}
+	var cfo []ChangedFileObject
+	for _, f := range files {
+		cfo = append(cfo, ChangedFileObject{Path: f})
+	}
+	return cfo, nil
+}
+
+// UpdateCommitStatus for a given commit.
+func (m *GiteaClient) UpdateCommitStatus(commitRef, baseContext, statusContext, status, targetURL, description string) error {
+	if baseContext == "" {
+		baseContext = "concourse-ci"
+	}
+
+	if statusContext == "" {
+		statusContext = "status"
+	}
+
+	if targetURL == "" {
+		targetURL = buildURL()
+	}
+
+	if description == "" {
+		description = fmt.Sprintf("Concourse CI build %s", status)
+	}
+
+	body := map[string]string{
+		"state":       strings.ToLower(status),
+		"context":     path.Join(baseContext, statusContext),
+		"target_url":  targetURL,
+		"description": description,
+	}
+	_, err := m.api.do(http.MethodPost, m.repoPath("statuses", commitRef), nil, body, nil)
+	return err
+}
+
+// UpdateCheckRun is not supported by Gitea.
+func (m *GiteaClient) UpdateCheckRun(commitRef string, run CheckRun) error {
+	return errors.New("check runs are not supported by gitea")
+}
+
+// CreateReview submits a review on the given commit of a pull request, where inline comments
+// are placed by their line in the new (right) or old (left) version of the file.
+func (m *GiteaClient) CreateReview(prNumber, commitRef string, review Review) error {
+	type comment struct {
+		Path        string `json:"path"`
+		Body        string `json:"body"`
+		NewPosition int    `json:"new_position,omitempty"`
+		OldPosition int    `json:"old_position,omitempty"`
+	}
+	body := struct {
+		CommitID string    `json:"commit_id"`
+		Event    string    `json:"event"`
+		Body     string    `json:"body,omitempty"`
+		Comments []comment `json:"comments,omitempty"`
+	}{
+		CommitID: commitRef,
+		Event:    review.Event,
+		Body:     review.Body,
+	}
+	if review.Event == "APPROVE" {
+		body.Event = "APPROVED"
+	}
+	for _, c := range review.Comments {
+		rc := comment{Path: c.Path, Body: c.Body, NewPosition: c.Line}
+		if c.Side == "LEFT" {
+			rc.NewPosition, rc.OldPosition = 0, c.Line
+		}
+		body.Comments = append(body.Comments, rc)
+	}
+
+	_, err := m.api.do(http.MethodPost, m.repoPath("pulls", prNumber, "reviews"), nil, body, nil)
+	return err
+}
+
+// MergePullRequest if the head of the pull request is still at the given commit, and
+// returns the SHA of the merge commit.
+func (m *GiteaClient) MergePullRequest(prNumber, commitRef string, merge Merge) (string, error) {
+	var pr giteaPullRequest
+	if _, err := m.api.do(http.MethodGet, m.repoPath("pulls", prNumber), nil, nil, &pr); err != nil {
+		return "", err
+	}
+	if pr.Merged {
+		return "", errors.New("pull request has already been merged")
+	}
+	if pr.State != "open" {
+		return "", fmt.Errorf("pull request is %s", pr.State)
+	}
+	if pr.Head.SHA != commitRef {
+		return "", fmt.Errorf("head of the pull request has moved from %s to %s", commitRef, pr.Head.SHA)
+	}
+	// Mergeability may still be being checked, in which case we let the merge decide.
+	if pr.pullRequestObject().Mergeable == MergeableStateConflicting {
+		return "", errors.New("pull request is not mergeable (state: conflicting)")
+	}
+
+	body := giteaMergeBody(commitRef, merge)
+	body["delete_branch_after_merge"] = merge.DeleteBranch
+	if err := m.merge(prNumber, body); err != nil {
+		return "", err
+	}
+
+	// The merge commit is not part of the response.
+	if _, err := m.api.do(http.MethodGet, m.repoPath("pulls", prNumber), nil, nil, &pr); err != nil {
+		return "", fmt.Errorf("failed to get merge commit: %s", err)
+	}
+	return pr.MergeCommitSHA, nil
+}
+
+// EnableAutoMerge for a pull request, so that it is merged by Gitea once all status checks
+// have succeeded. Fails if the head is no longer at the given commit.
+func (m *GiteaClient) EnableAutoMerge(pullRequestID, commitRef string, merge Merge) error {
+	body := giteaMergeBody(commitRef, merge)
+	body["merge_when_checks_succeed"] = true
+	return m.merge(pullRequestID, body)
+}
+
+func (m *GiteaClient) merge(prNumber string, body map[string]interface{}) error {
+	resp, err := m.api.do(http.MethodPost, m.repoPath("pulls", prNumber, "merge"), nil, body, nil)
+	if err != nil {
+		if resp != nil {
+			switch resp.StatusCode {
+			case http.StatusMethodNotAllowed:
+				return fmt.Errorf("pull request is not mergeable: %s", err)
+			case http.StatusConflict:
+				return fmt.Errorf("head of the pull request has moved: %s", err)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// giteaMergeBody returns the parameters for merging a pull request.
+func giteaMergeBody(commitRef string, merge Merge) map[string]interface{} {
+	method := merge.Method
+	if method == "" {
+		method = "merge"
+	}
+	body := map[string]interface{}{
+		"Do":             method,
+		"head_commit_id": commitRef,
+	}
+	if merge.CommitTitle != "" {
+		body["MergeTitleField"] = merge.CommitTitle
+	}
+	if merge.CommitMessage != "" {
+		body["MergeMessageField"] = merge.CommitMessage
+	}
+	return body
+}
+
+// EnqueuePullRequest is not supported by Gitea.
+func (m *GiteaClient) EnqueuePullRequest(pullRequestID, commitRef string) error {
+	return errors.New("merge queues are not supported by gitea")
+}
+
+// AddLabels to a pull request. Labels that do not exist in the repository are created
+// if createMissing is true, otherwise an error is returned.
+func (m *GiteaClient) AddLabels(prNumber string, labels []string, createMissing bool) error {
+	existing, err := m.listLabels()
+	if err != nil {
+		return err
+	}
+
+	var ids []int64
+	for _, l := range labels {
+		if id, ok := existing[strings.ToLower(l)]; ok {
+			ids = append(ids, id)
+			continue
+		}
+		if !createMissing {
+			return fmt.Errorf("label '%s' does not exist", l)
+		}
+		var label giteaLabel
+		body := map[string]string{"name": l, "color": "#" + defaultLabelColor}
+		if _, err := m.api.do(http.MethodPost, m.repoPath("labels"), nil, body, &label); err != nil {
+			return fmt.Errorf("failed to create label '%s': %s", l, err)
+		}
+		existing[strings.ToLower(l)] = label.ID
+		ids = append(ids, label.ID)
+	}
+
+	body := map[string][]int64{"labels": ids}
+	_, err = m.api.do(http.MethodPost, m.repoPath("issues", prNumber, "labels"), nil, body, nil)
+	return err
+}
+
+// RemoveLabels from a pull request. Labels that are not set on the pull request are ignored.
+func (m *GiteaClient) RemoveLabels(prNumber string, labels []string) error {
+	existing, err := m.listLabels()
+	if err != nil {
+		return err
+	}
+
+	for _, l := range labels {
+		id, ok := existing[strings.ToLower(l)]
+		if !ok {
+			continue
+		}
+		resp, err := m.api.do(http.MethodDelete, m.repoPath("issues", prNumber, "labels", strconv.FormatInt(id, 10)), nil, nil, nil)
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return fmt.Errorf("failed to remove label '%s': %s", l, err)
+		}
+	}
+	return nil
+}
+
+// listLabels returns the IDs of the labels in the repository by their (lower case) name.
+func (m *GiteaClient) listLabels() (map[string]int64, error) {
+	labels := make(map[string]int64)
+	var page []giteaLabel
+	err := m.api.list(m.repoPath("labels"), nil, &page, func() bool {
+		for _, l := range page {
+			labels[strings.ToLower(l.Name)] = l.ID
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// GetComment on a pull request by its ID. Comment IDs are unique within the repository, so
+// the pull request number is not used.
+func (m *GiteaClient) GetComment(prNumber, commentID string) (*CommentObject, error) {
+	var c giteaComment
+	if _, err := m.api.do(http.MethodGet, m.repoPath("issues", "comments", commentID), nil, nil, &c); err != nil {
+		return nil, err
+	}
+	comment := c.commentObject()
+	return &comment, nil
+}
+
+// ListTeamMembers returns the logins of all members of a team (by name) in an organization.
+func (m *GiteaClient) ListTeamMembers(org, team string) ([]string, error) {
+	var search struct {
+		Data []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	query := url.Values{"q": {team}}
+	if _, err := m.api.do(http.MethodGet, "orgs/"+url.PathEscape(org)+"/teams/search", query, nil, &search); err != nil {
+		return nil, err
+	}
+	var id int64
+	for _, t := range search.Data {
+		if strings.EqualFold(t.Name, team) {
+			id = t.ID
+		}
+	}
+	if id == 0 {
+		return nil, fmt.Errorf("team '%s/%s' does not exist", org, team)
+	}
+
+	var members []string
+	var page []giteaUser
+	err := m.api.list("teams/"+strconv.FormatInt(id, 10)+"/members", nil, &page, func() bool {
+		for _, u := range page {
+			members = append(members, u.Login)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// repoPath returns the API path of the repository followed by the (escaped) path segments.
+func (m *GiteaClient) repoPath(segments ...string) string {
+	p := "repos/" + url.PathEscape(m.Owner) + "/" + url.PathEscape(m.Repository)
+	for _, s := range segments {
+		p += "/" + url.PathEscape(s)
+	}
+	return p
+}
+
+// pullRequestObject translates the pull request, where the repository URL is that of the
+// base repository.
+func (pr *giteaPullRequest) pullRequestObject() PullRequestObject {
+	p := PullRequestObject{
+		ID:                strconv.Itoa(pr.Number),
+		Number:            pr.Number,
+		Title:             pr.Title,
+		URL:               pr.HTMLURL,
+		BaseRefName:       pr.Base.Ref,
+		HeadRefName:       pr.Head.Ref,
+		IsCrossRepository: pr.Head.RepoID != pr.Base.RepoID,
+		IsDraft:           pr.Draft,
+		Mergeable:         MergeableStateMergeable,
+		UpdatedAt:         pr.UpdatedAt,
+	}
+	p.Repository.URL = pr.Base.Repo.HTMLURL
+	// Gitea also reports that a pull request is not mergeable while it is being checked, so
+	// it is only conflicting if there are conflicted files.
+	switch {
+	case len(pr.ConflictedFiles) > 0:
+		p.Mergeable = MergeableStateConflicting
+	case !pr.Mergeable:
+		p.Mergeable = MergeableStateUnknown
+	}
+	p.Author.Login = pr.User.Login
+	return p
+}
+
+func (c *giteaCommit) commitObject() CommitObject {
+	commit := CommitObject{
+		ID:            c.SHA,
+		OID:           c.SHA,
+		CommittedDate: githubv4.DateTime{Time: c.Commit.Committer.Date},
+		Message:       c.Commit.Message,
+	}
+	if c.Author != nil {
+		commit.Author.User.Login = c.Author.Login
+	}
+	return commit
+}
+
+func (c *giteaComment) commentObject() CommentObject {
+	comment := CommentObject{
+		DatabaseId:        c.ID,
+		Body:              c.Body,
+		CreatedAt:         c.CreatedAt,
+		AuthorAssociation: CommentAuthorAssociationNone,
+	}
+	comment.Author.Login = c.User.Login
+	return comment
+}
@@ -0,0 +1,424 @@
+package resource_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+// giteaRepository is the API path of the test repository.
+const giteaRepository = "/api/v1/repos/itsdalmo/test-repository"
+
+func newGiteaTestClient(t *testing.T, server *httptest.Server, source resource.Source) *resource.GiteaClient {
+	source.Provider = "gitea"
+	source.Repository = "itsdalmo/test-repository"
+	source.AccessToken = "oauthtoken"
+	source.Endpoint = server.URL + "/api/v1"
+	require.NoError(t, source.Validate())
+
+	client, err := resource.NewGiteaClient(&source, nil)
+	require.NoError(t, err)
+	return client
+}
+
+var giteaPullRequests = `[
+	{
+		"number": 2,
+		"title": "second",
+		"html_url": "https://gitea.example.com/itsdalmo/test-repository/pulls/2",
+		"draft": true,
+		"mergeable": false,
+		"labels": [{"id": 1, "name": "bug"}],
+		"user": {"login": "someone"},
+		"head": {"ref": "feature", "sha": "oid2", "repo_id": 2},
+		"base": {"ref": "master", "sha": "base", "repo_id": 1, "repo": {"html_url": "https://gitea.example.com/itsdalmo/test-repository"}}
+	}
+]`
+
+func TestGiteaClientListOpenPullRequests(t *testing.T) {
+	tests := []struct {
+		description string
+		source      resource.Source
+		responses   map[string]string
+		expected    resource.PullRequest
+	}{
+		{
+			description: "lists pull requests",
+			responses:   map[string]string{},
+			expected: resource.PullRequest{
+				PullRequestObject: resource.PullRequestObject{Mergeable: resource.MergeableStateUnknown},
+				Labels:            []resource.LabelObject{{Name: "bug"}},
+			},
+		},
+		{
+			description: "lists pull requests with conflicts",
+			responses: map[string]string{
+				"GET " + giteaRepository + "/pulls": strings.Replace(giteaPullRequests, `"mergeable": false,`, `"mergeable": false, "conflicted_files": ["README.md"],`, 1),
+			},
+			expected: resource.PullRequest{
+				PullRequestObject: resource.PullRequestObject{Mergeable: resource.MergeableStateConflicting},
+				Labels:            []resource.LabelObject{{Name: "bug"}},
+			},
+		},
+		{
+			description: "requests the details used by the source configuration",
+			source: resource.Source{
+				RequiredReviewApprovals: 1,
+				TriggerPhrase:           "test this",
+				Labels:                  []string{"bug"},
+				RequiredStatuses:        []resource.RequiredStatus{{Context: "build"}},
+				TrackBaseBranch:         true,
+			},
+			responses: map[string]string{
+				"GET " + giteaRepository + "/collaborators":       `[{"login":"collaborator"}]`,
+				"GET " + giteaRepository + "/pulls/2/reviews":     `[{"state":"APPROVED"},{"state":"APPROVED","stale":true},{"state":"COMMENT"}]`,
+				"GET " + giteaRepository + "/issues/2/comments":   `[{"id":10,"body":"test this","created_at":"2020-01-03T00:00:00Z","user":{"login":"collaborator"}},{"id":12,"body":"test this","created_at":"2020-01-04T00:00:00Z","user":{"login":"someone"}}]`,
+				"GET " + giteaRepository + "/issues/2/timeline":   `[{"type":"comment","body":"hi"},{"type":"label","body":"1","created_at":"2020-01-05T00:00:00Z","label":{"name":"bug"}},{"type":"label","body":"","created_at":"2020-01-06T00:00:00Z","label":{"name":"wip"}}]`,
+				"GET " + giteaRepository + "/commits/oid2/status": `{"statuses":[{"context":"build","status":"failure","updated_at":"2020-01-07T00:00:00Z"}]}`,
+				"GET " + giteaRepository + "/branches/master":     `{"commit":{"id":"base","message":"base","timestamp":"2020-01-08T00:00:00Z"}}`,
+			},
+			expected: resource.PullRequest{
+				PullRequestObject:   resource.PullRequestObject{Mergeable: resource.MergeableStateUnknown},
+				ApprovedReviewCount: 1,
+				Labels:              []resource.LabelObject{{Name: "bug"}},
+				Comments: []resource.CommentObject{
+					giteaTestComment(10, "collaborator", resource.CommentAuthorAssociationCollaborator, time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)),
+					giteaTestComment(12, "someone", resource.CommentAuthorAssociationNone, time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)),
+				},
+				LabelEvents: []resource.LabelEvent{
+					{Name: "bug", Added: true, CreatedAt: time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC)},
+					{Name: "wip", Added: false, CreatedAt: time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)},
+				},
+				Statuses: []resource.Status{
+					{Context: "build", State: "failure", UpdatedAt: time.Date(2020, 1, 7, 0, 0, 0, 0, time.UTC)},
+				},
+				BaseTip: resource.CommitObject{ID: "base", OID: "base", Message: "base", CommittedDate: githubv4.DateTime{Time: time.Date(2020, 1, 8, 0, 0, 0, 0, time.UTC)}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			responses := map[string]string{
+				"GET " + giteaRepository + "/pulls":            giteaPullRequests,
+				"GET " + giteaRepository + "/git/commits/oid2": `{"sha":"oid2","commit":{"message":"commit","committer":{"date":"2020-01-01T00:00:00Z"}},"author":{"login":"committer"}}`,
+			}
+			for k, v := range tc.responses {
+				responses[k] = v
+			}
+			g, server := newAPIStandIn(t, responses)
+			g.links = true
+			defer server.Close()
+
+			pulls, err := newGiteaTestClient(t, server, tc.source).ListOpenPullRequests()
+			require.NoError(t, err)
+			require.Len(t, pulls, 1)
+
+			// Only the endpoints used by the source configuration are requested.
+			assert.Len(t, g.requests, len(responses))
+
+			p := pulls[0]
+			assert.Equal(t, "2", p.ID)
+			assert.Equal(t, 2, p.Number)
+			assert.Equal(t, "second", p.Title)
+			assert.Equal(t, "https://gitea.example.com/itsdalmo/test-repository", p.Repository.URL)
+			assert.Equal(t, "master", p.BaseRefName)
+			assert.Equal(t, "feature", p.HeadRefName)
+			assert.True(t, p.IsCrossRepository)
+			assert.True(t, p.IsDraft)
+			assert.Equal(t, tc.expected.Mergeable, p.Mergeable)
+			assert.Equal(t, "someone", p.Author.Login)
+			assert.Equal(t, "oid2", p.Tip.OID)
+			assert.Equal(t, "committer", p.Tip.Author.User.Login)
+			assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), p.Tip.CommittedDate.Time)
+
+			assert.Equal(t, tc.expected.ApprovedReviewCount, p.ApprovedReviewCount)
+			assert.Equal(t, tc.expected.Labels, p.Labels)
+			assert.Equal(t, tc.expected.Comments, p.Comments)
+			assert.Equal(t, tc.expected.LabelEvents, p.LabelEvents)
+			assert.Equal(t, tc.expected.Statuses, p.Statuses)
+			assert.Equal(t, tc.expected.BaseTip, p.BaseTip)
+		})
+	}
+}
+
+func TestGiteaClientListModifiedFiles(t *testing.T) {
+	g, server := newAPIStandIn(t, map[string]string{
+		"GET " + giteaRepository + "/pulls/1/files":        `[{"filename":"README.md"}]`,
+		"GET " + giteaRepository + "/pulls/1/files?page=2": `[{"filename":"terraform/main.tf"}]`,
+		"GET " + giteaRepository + "/compare/base...head":  `{"commits":[{"files":[{"filename":"main.go"},{"filename":"go.mod"}]},{"files":[{"filename":"main.go"}]}]}`,
+	})
+	g.links = true
+	defer server.Close()
+	client := newGiteaTestClient(t, server, resource.Source{})
+
+	files, err := client.ListModifiedFiles(1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"README.md", "terraform/main.tf"}, files)
+	}
+
+	files, err = client.ListModifiedFilesBetween("base", "head")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"main.go", "go.mod"}, files)
+	}
+	assert.Len(t, g.requests, 3)
+}
+
+func TestGiteaClientGetPullRequest(t *testing.T) {
+	g, server := newAPIStandIn(t, map[string]string{
+		"GET " + giteaRepository + "/pulls/2":                `{"number":2,"title":"second","base":{"ref":"master","repo":{"html_url":"https://gitea.example.com/itsdalmo/test-repository"}}}`,
+		"GET " + giteaRepository + "/pulls/2/commits":        `[{"sha":"oid3","commit":{"message":"third"}},{"sha":"oid2","commit":{"message":"second"}}]`,
+		"GET " + giteaRepository + "/pulls/2/commits?page=2": `[{"sha":"oid1","commit":{"message":"first"}}]`,
+	})
+	g.links = true
+	defer server.Close()
+	client := newGiteaTestClient(t, server, resource.Source{})
+
+	pull, err := client.GetPullRequest("2", "oid1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, pull.Number)
+		assert.Equal(t, "master", pull.BaseRefName)
+		assert.Equal(t, "https://gitea.example.com/itsdalmo/test-repository", pull.Repository.URL)
+		assert.Equal(t, "oid1", pull.Tip.OID)
+		assert.Equal(t, "first", pull.Tip.Message)
+	}
+
+	_, err = client.GetPullRequest("2", "missing")
+	assert.EqualError(t, err, "commit with ref 'missing' does not exist")
+}
+
+func TestGiteaClientUpdateCommitStatus(t *testing.T) {
+	key := "POST " + giteaRepository + "/statuses/oid"
+	g, server := newAPIStandIn(t, map[string]string{key: `{}`})
+	defer server.Close()
+
+	err := newGiteaTestClient(t, server, resource.Source{}).UpdateCommitStatus("oid", "ci", "unit", "failure", "https://ci.example.com", "")
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"context":"ci/unit","description":"Concourse CI build failure","state":"failure","target_url":"https://ci.example.com"}`, g.bodies[key])
+	}
+}
+
+func TestGiteaClientUpdateComment(t *testing.T) {
+	tests := []struct {
+		description     string
+		comments        string
+		expectedRequest string
+	}{
+		{
+			description:     "creates a comment when none has the key",
+			comments:        `[{"id":1,"body":"hello","user":{"login":"bot"}}]`,
+			expectedRequest: "POST " + giteaRepository + "/issues/1/comments",
+		},
+		{
+			description: "updates the comment with the same key",
+			comments: `[
+				{"id":3,"body":"plan\n\n<!-- github-pr-resource: plan -->","user":{"login":"bot"}},
+				{"id":4,"body":"lint\n\n<!-- github-pr-resource: lint -->","user":{"login":"bot"}}
+			]`,
+			expectedRequest: "PATCH " + giteaRepository + "/issues/comments/3",
+		},
+		{
+			description:     "ignores comments by other users",
+			comments:        `[{"id":3,"body":"> <!-- github-pr-resource: plan -->","user":{"login":"someone"}}]`,
+			expectedRequest: "POST " + giteaRepository + "/issues/1/comments",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			g, server := newAPIStandIn(t, map[string]string{
+				"GET /api/v1/user": `{"login":"bot"}`,
+				"GET " + giteaRepository + "/issues/1/comments":   tc.comments,
+				"POST " + giteaRepository + "/issues/1/comments":  `{}`,
+				"PATCH " + giteaRepository + "/issues/comments/3": `{}`,
+			})
+			defer server.Close()
+
+			err := newGiteaTestClient(t, server, resource.Source{}).UpdateComment("1", "plan", "new plan")
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expectedRequest, g.requests[len(g.requests)-1])
+				assert.Equal(t, `{"body":"new plan\n\n<!-- github-pr-resource: plan -->"}`, g.bodies[tc.expectedRequest])
+			}
+		})
+	}
+}
+
+func TestGiteaClientCreateReview(t *testing.T) {
+	tests := []struct {
+		description  string
+		review       resource.Review
+		expectedBody string
+	}{
+		{
+			description:  "approves the pull request",
+			review:       resource.Review{Event: "APPROVE"},
+			expectedBody: `{"commit_id":"oid","event":"APPROVED"}`,
+		},
+		{
+			description: "places inline comments by side",
+			review: resource.Review{Event: "REQUEST_CHANGES", Body: "no", Comments: []resource.ReviewComment{
+				{Path: "main.go", Line: 3, Side: "RIGHT", Body: "new"},
+				{Path: "main.go", Line: 2, Side: "LEFT", Body: "old"},
+			}},
+			expectedBody: `{"commit_id":"oid","event":"REQUEST_CHANGES","body":"no","comments":[{"path":"main.go","body":"new","new_position":3},{"path":"main.go","body":"old","old_position":2}]}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			key := "POST " + giteaRepository + "/pulls/1/reviews"
+			g, server := newAPIStandIn(t, map[string]string{key: `{}`})
+			defer server.Close()
+
+			err := newGiteaTestClient(t, server, resource.Source{}).CreateReview("1", "oid", tc.review)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expectedBody, g.bodies[key])
+			}
+		})
+	}
+}
+
+func TestGiteaClientMergePullRequest(t *testing.T) {
+	tests := []struct {
+		description  string
+		pullRequest  string
+		merge        resource.Merge
+		result       string
+		expectedBody string
+		expectedSHA  string
+		expectedErr  string
+	}{
+		{
+			description:  "merges the pull request",
+			pullRequest:  `{"number":1,"state":"open","mergeable":true,"head":{"sha":"oid"},"merge_commit_sha":"merged"}`,
+			merge:        resource.Merge{Method: "squash", CommitTitle: "title", CommitMessage: "message", DeleteBranch: true},
+			expectedBody: `{"Do":"squash","MergeMessageField":"message","MergeTitleField":"title","delete_branch_after_merge":true,"head_commit_id":"oid"}`,
+			expectedSHA:  "merged",
+		},
+		{
+			description:  "merges the pull request while it is being checked",
+			pullRequest:  `{"number":1,"state":"open","mergeable":false,"head":{"sha":"oid"},"merge_commit_sha":"merged"}`,
+			merge:        resource.Merge{Method: "merge"},
+			expectedBody: `{"Do":"merge","delete_branch_after_merge":false,"head_commit_id":"oid"}`,
+			expectedSHA:  "merged",
+		},
+		{
+			description: "fails if the pull request has conflicts",
+			pullRequest: `{"number":1,"state":"open","mergeable":false,"conflicted_files":["README.md"],"head":{"sha":"oid"}}`,
+			merge:       resource.Merge{Method: "merge"},
+			expectedErr: "pull request is not mergeable (state: conflicting)",
+		},
+		{
+			description: "fails if the head has moved",
+			pullRequest: `{"number":1,"state":"open","mergeable":true,"head":{"sha":"new"}}`,
+			merge:       resource.Merge{Method: "merge"},
+			expectedErr: "head of the pull request has moved from oid to new",
+		},
+		{
+			description: "fails if the pull request has been merged",
+			pullRequest: `{"number":1,"state":"closed","merged":true,"head":{"sha":"oid"}}`,
+			merge:       resource.Merge{Method: "merge"},
+			expectedErr: "pull request has already been merged",
+		},
+		{
+			description:  "fails if the merge is not allowed",
+			pullRequest:  `{"number":1,"state":"open","mergeable":true,"head":{"sha":"oid"}}`,
+			merge:        resource.Merge{Method: "merge"},
+			result:       `405 Please try again later`,
+			expectedBody: `{"Do":"merge","delete_branch_after_merge":false,"head_commit_id":"oid"}`,
+			expectedErr:  "pull request is not mergeable",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			key := "POST " + giteaRepository + "/pulls/1/merge"
+			g, server := newAPIStandIn(t, map[string]string{
+				"GET " + giteaRepository + "/pulls/1": tc.pullRequest,
+				key:                                   tc.result,
+			})
+			defer server.Close()
+
+			sha, err := newGiteaTestClient(t, server, resource.Source{}).MergePullRequest("1", "oid", tc.merge)
+			if tc.expectedErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.expectedErr)
+				}
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tc.expectedSHA, sha)
+			}
+			assert.Equal(t, tc.expectedBody, g.bodies[key])
+		})
+	}
+}
+
+func TestGiteaClientLabels(t *testing.T) {
+	tests := []struct {
+		description      string
+		createMissing    bool
+		labels           []string
+		expectedRequests []string
+		expectedBody     string
+		expectedErr      string
+	}{
+		{
+			description:      "adds existing labels by id",
+			labels:           []string{"Bug", "review"},
+			expectedRequests: []string{"GET " + giteaRepository + "/labels", "GET " + giteaRepository + "/labels?page=2", "POST " + giteaRepository + "/issues/1/labels"},
+			expectedBody:     `{"labels":[1,2]}`,
+		},
+		{
+			description:      "fails for missing labels",
+			labels:           []string{"bug", "missing"},
+			expectedRequests: []string{"GET " + giteaRepository + "/labels", "GET " + giteaRepository + "/labels?page=2"},
+			expectedErr:      "label 'missing' does not exist",
+		},
+		{
+			description:      "creates missing labels",
+			createMissing:    true,
+			labels:           []string{"bug", "missing"},
+			expectedRequests: []string{"GET " + giteaRepository + "/labels", "GET " + giteaRepository + "/labels?page=2", "POST " + giteaRepository + "/labels", "POST " + giteaRepository + "/issues/1/labels"},
+			expectedBody:     `{"labels":[1,3]}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			key := "POST " + giteaRepository + "/issues/1/labels"
+			g, server := newAPIStandIn(t, map[string]string{
+				"GET " + giteaRepository + "/labels":        `[{"id":1,"name":"bug"}]`,
+				"GET " + giteaRepository + "/labels?page=2": `[{"id":2,"name":"review"}]`,
+				"POST " + giteaRepository + "/labels":       `{"id":3,"name":"missing"}`,
+				key:                                         `[]`,
+			})
+			g.links = true
+			defer server.Close()
+
+			err := newGiteaTestClient(t, server, resource.Source{}).AddLabels("1", tc.labels, tc.createMissing)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedRequests, g.requests)
+			assert.Equal(t, tc.expectedBody, g.bodies[key])
+		})
+	}
+}
+
+func giteaTestComment(id int64, author string, association resource.CommentAuthorAssociation, createdAt time.Time) resource.CommentObject {
+	c := resource.CommentObject{
+		DatabaseId:        id,
+		Body:              "test this",
+		CreatedAt:         createdAt,
+		AuthorAssociation: association,
+	}
+	c.Author.Login = author
+	return c
+}
@@ -11,23 +11,18 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v28/github"
 	"github.com/shurcooL/githubv4"
 	"golang.org/x/oauth2"
 )
 
-// Github for testing purposes.
-//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -o fakes/fake_github.go . Github
-type Github interface {
-	ListOpenPullRequests() ([]*PullRequest, error)
-	ListModifiedFiles(int) ([]string, error)
-	PostComment(string, string) error
-	GetPullRequest(string, string) (*PullRequest, error)
-	GetChangedFiles(string, string) ([]ChangedFileObject, error)
-	UpdateCommitStatus(string, string, string, string, string, string) error
-	DeletePreviousComments(string) error
-}
+// defaultLabelColor is used for labels created by the resource.
+const defaultLabelColor = "ededed"
+
+// maxAnnotationsPerRequest is the number of check run annotations Github accepts per request.
+const maxAnnotationsPerRequest = 50
 
 // GithubClient for handling requests to the Github V3 and V4 APIs.
 type GithubClient struct {
@@ -35,31 +30,26 @@ type GithubClient struct {
 	V4         *githubv4.Client
 	Repository string
 	Owner      string
+	source     Source
 }
 
-// NewGithubClient ...
-func NewGithubClient(s *Source) (*GithubClient, error) {
+// NewGithubClient creates a Github client, which uses a token source for the
+// source configuration if tokenSource is nil.
+func NewGithubClient(s *Source, tokenSource oauth2.TokenSource) (*GithubClient, error) {
 	owner, repository, err := parseRepository(s.Repository)
 	if err != nil {
 		return nil, err
 	}
 
-	// Skip SSL verification for self-signed certificates
-	// source: https://github.com/google/go-github/pull/598#issuecomment-333039238
-	var ctx context.Context
-	if s.SkipSSLVerification {
-		insecureClient := &http.Client{Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+	httpClient := newHTTPClient(s)
+	if tokenSource == nil {
+		if tokenSource, err = NewTokenSource(s, httpClient); err != nil {
+			return nil, err
 		}
-		ctx = context.WithValue(context.TODO(), oauth2.HTTPClient, insecureClient)
-	} else {
-		ctx = context.TODO()
 	}
 
-	client := oauth2.NewClient(ctx, oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: s.AccessToken},
-	))
+	ctx := context.WithValue(context.TODO(), oauth2.HTTPClient, httpClient)
+	client := oauth2.NewClient(ctx, tokenSource)
 
 	var v3 *github.Client
 	if s.V3Endpoint != "" {
@@ -94,6 +84,7 @@ func NewGithubClient(s *Source) (*GithubClient, error) {
 		V4:         v4,
 		Owner:      owner,
 		Repository: repository,
+		source:     *s,
 	}, nil
 }
 
@@ -111,7 +102,27 @@ func (m *GithubClient) ListOpenPullRequests() ([]*PullRequest, error) {
 						Commits struct {
 							Edges []struct {
 								Node struct {
-									Commit CommitObject
+									Commit struct {
+										CommitObject
+										StatusCheckRollup struct {
+											Contexts struct {
+												Nodes []struct {
+													StatusContext struct {
+														Context   string
+														State     string
+														CreatedAt githubv4.DateTime
+													} `graphql:"... on StatusContext"`
+													CheckRun struct {
+														Name        string
+														Status      string
+														Conclusion  string
+														StartedAt   *githubv4.DateTime
+														CompletedAt *githubv4.DateTime
+													} `graphql:"... on CheckRun"`
+												}
+											} `graphql:"contexts(first:$contextsFirst)"`
+										} `graphql:"statusCheckRollup: statusCheckRollup @include(if:$withStatuses)"`
+									}
 								}
 							}
 						} `graphql:"commits(last:$commitsLast)"`
@@ -122,6 +133,62 @@ func (m *GithubClient) ListOpenPullRequests() ([]*PullRequest, error) {
 								}
 							}
 						} `graphql:"labels(first:$labelsFirst)"`
+						ReadyForReview struct {
+							Nodes []struct {
+								ReadyForReviewEvent struct {
+									CreatedAt githubv4.DateTime
+								} `graphql:"... on ReadyForReviewEvent"`
+							}
+						} `graphql:"readyForReview: timelineItems(last:1,itemTypes:[READY_FOR_REVIEW_EVENT]) @include(if:$ignoreDrafts)"`
+						Pushes struct {
+							Nodes []struct {
+								Typename                string `graphql:"__typename"`
+								HeadRefForcePushedEvent struct {
+									CreatedAt   githubv4.DateTime
+									AfterCommit struct {
+										OID string
+									}
+								} `graphql:"... on HeadRefForcePushedEvent"`
+								PullRequestCommit struct {
+									Commit struct {
+										CommittedDate githubv4.DateTime
+									}
+								} `graphql:"... on PullRequestCommit"`
+							}
+							PageInfo struct {
+								HasPreviousPage bool
+							}
+						} `graphql:"pushes: timelineItems(last:$pushesLast,itemTypes:[PULL_REQUEST_COMMIT,HEAD_REF_FORCE_PUSHED_EVENT])"`
+						Files struct {
+							Nodes    []ChangedFileObject
+							PageInfo struct {
+								HasNextPage bool
+							}
+						} `graphql:"files: files(first:$filesFirst) @include(if:$withFiles)"`
+						LabelEvents struct {
+							Nodes []struct {
+								Typename     string `graphql:"__typename"`
+								LabeledEvent struct {
+									CreatedAt githubv4.DateTime
+									Label     LabelObject
+								} `graphql:"... on LabeledEvent"`
+								UnlabeledEvent struct {
+									CreatedAt githubv4.DateTime
+									Label     LabelObject
+								} `graphql:"... on UnlabeledEvent"`
+							}
+						} `graphql:"labelEvents: timelineItems(last:$labelEventsLast,itemTypes:[LABELED_EVENT,UNLABELED_EVENT]) @include(if:$withLabels)"`
+						Comments struct {
+							Nodes []CommentObject
+						} `graphql:"comments(last:$commentsLast) @include(if:$withComments)"`
+						MergeQueueEntry struct {
+							HeadCommit CommitObject
+						} `graphql:"mergeQueueEntry: mergeQueueEntry @include(if:$mergeQueue)"`
+						BaseRef struct {
+							Target struct {
+								Commit CommitObject `graphql:"... on Commit"`
+							}
+						} `graphql:"baseRef: baseRef @include(if:$trackBaseBranch)"`
 					}
 				}
 				PageInfo struct {
@@ -141,6 +208,18 @@ func (m *GithubClient) ListOpenPullRequests() ([]*PullRequest, error) {
 		"commitsLast":     githubv4.Int(1),
 		"prReviewStates":  []githubv4.PullRequestReviewState{githubv4.PullRequestReviewStateApproved},
 		"labelsFirst":     githubv4.Int(100),
+		"pushesLast":      githubv4.Int(10),
+		"ignoreDrafts":    githubv4.Boolean(m.source.IgnoreDrafts),
+		"commentsLast":    githubv4.Int(100),
+		"withComments":    githubv4.Boolean(m.source.TriggerPhrase != ""),
+		"mergeQueue":      githubv4.Boolean(m.source.MergeQueue),
+		"trackBaseBranch": githubv4.Boolean(m.source.TrackBaseBranch),
+		"contextsFirst":   githubv4.Int(100),
+		"withStatuses":    githubv4.Boolean(len(m.source.RequiredStatuses) > 0),
+		"labelEventsLast": githubv4.Int(100),
+		"withLabels":      githubv4.Boolean(len(m.source.Labels) > 0 || len(m.source.IgnoreLabels) > 0),
+		"filesFirst":      githubv4.Int(100),
+		"withFiles":       githubv4.Boolean(len(m.source.Paths) > 0 || len(m.source.IgnorePaths) > 0),
 	}
 
 	var response []*PullRequest
@@ -154,12 +233,92 @@ func (m *GithubClient) ListOpenPullRequests() ([]*PullRequest, error) {
 				labels = append(labels, l.Node.LabelObject)
 			}
 
+			var readyForReview time.Time
+			for _, e := range p.Node.ReadyForReview.Nodes {
+				readyForReview = e.ReadyForReviewEvent.CreatedAt.Time
+			}
+
+			// The files are only used if all of them were listed, otherwise they are listed separately.
+			var files []string
+			if len(m.source.Paths) > 0 || len(m.source.IgnorePaths) > 0 {
+				if !p.Node.Files.PageInfo.HasNextPage {
+					files = make([]string, 0, len(p.Node.Files.Nodes))
+					for _, f := range p.Node.Files.Nodes {
+						files = append(files, f.Path)
+					}
+				}
+			}
+
+			var labelEvents []LabelEvent
+			for _, e := range p.Node.LabelEvents.Nodes {
+				switch e.Typename {
+				case "LabeledEvent":
+					labelEvents = append(labelEvents, LabelEvent{Name: e.LabeledEvent.Label.Name, Added: true, CreatedAt: e.LabeledEvent.CreatedAt.Time})
+				case "UnlabeledEvent":
+					labelEvents = append(labelEvents, LabelEvent{Name: e.UnlabeledEvent.Label.Name, CreatedAt: e.UnlabeledEvent.CreatedAt.Time})
+				}
+			}
+
 			for _, c := range p.Node.Commits.Edges {
+				// The head was pushed at or after the time of each push in the timeline, which is
+				// when it was force pushed, or when a pushed commit was committed. The time the tip
+				// was pushed is only known if the latest push was a force push to the tip.
+				var pushed, pushedAfter time.Time
+				pushes := p.Node.Pushes.Nodes
+				for i, e := range pushes {
+					t := e.PullRequestCommit.Commit.CommittedDate.Time
+					if e.Typename == "HeadRefForcePushedEvent" {
+						t = e.HeadRefForcePushedEvent.CreatedAt.Time
+						if i == len(pushes)-1 && e.HeadRefForcePushedEvent.AfterCommit.OID == c.Node.Commit.OID {
+							pushed = t
+						}
+					}
+					if t.After(pushedAfter) {
+						pushedAfter = t
+					}
+				}
+
+				// Statuses and check runs are combined, where the state of a check run is
+				// its conclusion once it has completed.
+				var statuses []Status
+				for _, n := range c.Node.Commit.StatusCheckRollup.Contexts.Nodes {
+					switch {
+					case n.StatusContext.Context != "":
+						state := strings.ToLower(n.StatusContext.State)
+						if state == "expected" {
+							state = "pending"
+						}
+						statuses = append(statuses, Status{Context: n.StatusContext.Context, State: state, UpdatedAt: n.StatusContext.CreatedAt.Time})
+					case n.CheckRun.Name != "":
+						status := Status{Context: n.CheckRun.Name, State: "pending"}
+						if n.CheckRun.StartedAt != nil {
+							status.UpdatedAt = n.CheckRun.StartedAt.Time
+						}
+						if n.CheckRun.Status == "COMPLETED" {
+							status.State = strings.ToLower(n.CheckRun.Conclusion)
+							if n.CheckRun.CompletedAt != nil {
+								status.UpdatedAt = n.CheckRun.CompletedAt.Time
+							}
+						}
+						statuses = append(statuses, status)
+					}
+				}
+
 				response = append(response, &PullRequest{
 					PullRequestObject:   p.Node.PullRequestObject,
-					Tip:                 c.Node.Commit,
+					Tip:                 c.Node.Commit.CommitObject,
+					PushedTime:          pushed,
+					PushedAfter:         pushedAfter,
+					PushesListed:        !p.Node.Pushes.PageInfo.HasPreviousPage,
 					ApprovedReviewCount: p.Node.Reviews.TotalCount,
 					Labels:              labels,
+					ReadyForReviewTime:  readyForReview,
+					Comments:            p.Node.Comments.Nodes,
+					MergeGroup:          p.Node.MergeQueueEntry.HeadCommit,
+					BaseTip:             p.Node.BaseRef.Target.Commit,
+					Statuses:            statuses,
+					LabelEvents:         labelEvents,
+					Files:               files,
 				})
 			}
 		}
@@ -200,6 +359,151 @@ func (m *GithubClient) ListModifiedFiles(prNumber int) ([]string, error) {
 	return files, nil
 }
 
+// ListModifiedFilesBetween two commits, using the compare API (which lists at most 300 files).
+func (m *GithubClient) ListModifiedFilesBetween(base, head string) ([]string, error) {
+	comparison, _, err := m.V3.Repositories.CompareCommits(context.TODO(), m.Owner, m.Repository, base, head)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range comparison.Files {
+		files = append(files, f.GetFilename())
+	}
+	return files, nil
+}
+
+// ListPullRequestCommits returns the commits in a pull request after the given commit, oldest
+// first. All commits are returned if the given commit is not part of the pull request (e.g.
+// after a force push).
+func (m *GithubClient) ListPullRequestCommits(prNumber int, after string) ([]CommitObject, error) {
+	var response []CommitObject
+	err := m.walkPullRequestCommits(prNumber, func(c CommitObject) bool {
+		if c.OID == after {
+			return false
+		}
+		response = append([]CommitObject{c}, response...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetPullRequestCommitBefore returns the SHA of the tip of a pull request at the given time, or
+// an empty string if there is none. It is taken from the pushes to the head branch when they can
+// be listed, and otherwise it is the latest commit that was committed at or before the time.
+func (m *GithubClient) GetPullRequestCommitBefore(prNumber int, before time.Time) (string, error) {
+	if sha, ok := m.headPushedBefore(prNumber, before); ok {
+		return sha, nil
+	}
+	var sha string
+	err := m.walkPullRequestCommits(prNumber, func(c CommitObject) bool {
+		if c.CommittedDate.Time.After(before) {
+			return true
+		}
+		sha = c.OID
+		return false
+	})
+	if err != nil {
+		return "", err
+	}
+	return sha, nil
+}
+
+// headPushedBefore returns the SHA that the head branch of a pull request was pushed to at or
+// before the given time, using the activity of the head repository. It returns false if the
+// activity cannot be listed (e.g. for forks the token cannot read) or does not go back far enough.
+func (m *GithubClient) headPushedBefore(prNumber int, before time.Time) (string, bool) {
+	pr, _, err := m.V3.PullRequests.Get(context.TODO(), m.Owner, m.Repository, prNumber)
+	if err != nil || pr.GetHead().GetRepo() == nil {
+		return "", false
+	}
+	repo := pr.GetHead().GetRepo()
+
+	query := url.Values{"ref": {"refs/heads/" + pr.GetHead().GetRef()}, "per_page": {"100"}}
+	u := fmt.Sprintf("repos/%s/%s/activity?%s", repo.GetOwner().GetLogin(), repo.GetName(), query.Encode())
+	req, err := m.V3.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return "", false
+	}
+	var activity []struct {
+		Before    string    `json:"before"`
+		After     string    `json:"after"`
+		Timestamp time.Time `json:"timestamp"`
+	}
+	if _, err := m.V3.Do(context.TODO(), req, &activity); err != nil || len(activity) == 0 {
+		return "", false
+	}
+
+	// The activity is listed with the latest first. If all of it happened after the given time
+	// the head is what it was before the first push, unless there may be more activity.
+	for _, a := range activity {
+		if !a.Timestamp.After(before) {
+			return nullSHA(a.After), true
+		}
+	}
+	if len(activity) == 100 {
+		return "", false
+	}
+	return nullSHA(activity[len(activity)-1].Before), true
+}
+
+// nullSHA returns the SHA, or an empty string if it is the null SHA used when a branch is
+// created or deleted.
+func nullSHA(sha string) string {
+	if strings.Trim(sha, "0") == "" {
+		return ""
+	}
+	return sha
+}
+
+// walkPullRequestCommits calls fn for the commits in a pull request, from the tip and
+// backwards, until fn returns false.
+func (m *GithubClient) walkPullRequestCommits(prNumber int, fn func(CommitObject) bool) error {
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				Commits struct {
+					Edges []struct {
+						Node struct {
+							Commit CommitObject
+						}
+					}
+					PageInfo struct {
+						StartCursor     githubv4.String
+						HasPreviousPage bool
+					}
+				} `graphql:"commits(last:$commitsLast,before:$commitsCursor)"`
+			} `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	vars := map[string]interface{}{
+		"repositoryOwner": githubv4.String(m.Owner),
+		"repositoryName":  githubv4.String(m.Repository),
+		"prNumber":        githubv4.Int(prNumber),
+		"commitsLast":     githubv4.Int(100),
+		"commitsCursor":   (*githubv4.String)(nil),
+	}
+
+	for {
+		if err := m.V4.Query(context.TODO(), &query, vars); err != nil {
+			return err
+		}
+		edges := query.Repository.PullRequest.Commits.Edges
+		for i := len(edges) - 1; i >= 0; i-- {
+			if !fn(edges[i].Node.Commit) {
+				return nil
+			}
+		}
+		if !query.Repository.PullRequest.Commits.PageInfo.HasPreviousPage {
+			return nil
+		}
+		vars["commitsCursor"] = query.Repository.PullRequest.Commits.PageInfo.StartCursor
+	}
+}
+
 // PostComment to a pull request or issue.
 func (m *GithubClient) PostComment(prNumber, comment string) error {
 	pr, err := strconv.Atoi(prNumber)
@@ -219,6 +523,72 @@ func (m *GithubClient) PostComment(prNumber, comment string) error {
 	return err
 }
 
+// UpdateComment on a pull request, identified by a hidden marker containing the key. The
+// comment is created if the viewer has not posted a comment with the same key before.
+func (m *GithubClient) UpdateComment(prNumber, key, comment string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	var query struct {
+		Viewer struct {
+			Login string
+		}
+		Repository struct {
+			PullRequest struct {
+				Comments struct {
+					Nodes []struct {
+						DatabaseId int64
+						Body       string
+						Author     struct {
+							Login string
+						}
+					}
+					PageInfo struct {
+						EndCursor   githubv4.String
+						HasNextPage bool
+					}
+				} `graphql:"comments(first:$commentsFirst,after:$commentsCursor)"`
+			} `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	vars := map[string]interface{}{
+		"repositoryOwner": githubv4.String(m.Owner),
+		"repositoryName":  githubv4.String(m.Repository),
+		"prNumber":        githubv4.Int(pr),
+		"commentsFirst":   githubv4.Int(100),
+		"commentsCursor":  (*githubv4.String)(nil),
+	}
+
+	marker := commentMarker(key)
+	body := github.String(comment + "\n\n" + marker)
+
+	var id int64
+	for {
+		if err := m.V4.Query(context.TODO(), &query, vars); err != nil {
+			return err
+		}
+		for _, c := range query.Repository.PullRequest.Comments.Nodes {
+			if c.Author.Login == query.Viewer.Login && strings.Contains(c.Body, marker) {
+				id = c.DatabaseId
+			}
+		}
+		if !query.Repository.PullRequest.Comments.PageInfo.HasNextPage {
+			break
+		}
+		vars["commentsCursor"] = query.Repository.PullRequest.Comments.PageInfo.EndCursor
+	}
+
+	if id == 0 {
+		_, _, err = m.V3.Issues.CreateComment(context.TODO(), m.Owner, m.Repository, pr, &github.IssueComment{Body: body})
+		return err
+	}
+	_, _, err = m.V3.Issues.EditComment(context.TODO(), m.Owner, m.Repository, id, &github.IssueComment{Body: body})
+	return err
+}
+
 // GetChangedFiles ...
 func (m *GithubClient) GetChangedFiles(prNumber string, commitRef string) ([]ChangedFileObject, error) {
 	pr, err := strconv.Atoi(prNumber)
@@ -334,7 +704,7 @@ func (m *GithubClient) UpdateCommitStatus(commitRef, baseContext, statusContext,
 	}
 
 	if targetURL == "" {
-		targetURL = strings.Join([]string{os.Getenv("ATC_EXTERNAL_URL"), "builds", os.Getenv("BUILD_ID")}, "/")
+		targetURL = buildURL()
 	}
 
 	if description == "" {
@@ -356,6 +726,266 @@ func (m *GithubClient) UpdateCommitStatus(commitRef, baseContext, statusContext,
 	return err
 }
 
+// UpdateCheckRun creates a check run for a given commit, or updates the check run with the
+// same name and external ID if it exists (not supported by V4 API).
+func (m *GithubClient) UpdateCheckRun(commitRef string, run CheckRun) error {
+	var id int64
+	if run.ExternalID != "" {
+		opt := &github.ListCheckRunsOptions{
+			CheckName:   github.String(run.Name),
+			Filter:      github.String("all"),
+			ListOptions: github.ListOptions{PerPage: 100},
+		}
+	Loop:
+		for {
+			result, response, err := m.V3.Checks.ListCheckRunsForRef(context.TODO(), m.Owner, m.Repository, commitRef, opt)
+			if err != nil {
+				return err
+			}
+			for _, r := range result.CheckRuns {
+				if r.GetExternalID() == run.ExternalID {
+					id = r.GetID()
+					break Loop
+				}
+			}
+			if response.NextPage == 0 {
+				break
+			}
+			opt.Page = response.NextPage
+		}
+	}
+
+	if run.DetailsURL == "" {
+		run.DetailsURL = buildURL()
+	}
+
+	// Github appends annotations on every update, so they are only sent when the check run
+	// is completed to avoid duplicates (e.g. when going from in_progress to completed).
+	annotations := run.Annotations
+	if run.Status != "completed" {
+		annotations = nil
+	}
+
+	// Github only accepts 50 annotations per request, so the remaining annotations are
+	// added by updating the check run.
+	var batches [][]Annotation
+	for len(annotations) > 0 {
+		n := maxAnnotationsPerRequest
+		if len(annotations) < n {
+			n = len(annotations)
+		}
+		batches = append(batches, annotations[:n])
+		annotations = annotations[n:]
+	}
+	if len(batches) == 0 {
+		batches = append(batches, nil)
+	}
+
+	var completedAt *github.Timestamp
+	if run.Status == "completed" {
+		completedAt = &github.Timestamp{Time: time.Now()}
+	}
+
+	for _, batch := range batches {
+		output := checkRunOutput(run, batch)
+		if id == 0 {
+			r, _, err := m.V3.Checks.CreateCheckRun(context.TODO(), m.Owner, m.Repository, github.CreateCheckRunOptions{
+				Name:        run.Name,
+				HeadSHA:     commitRef,
+				DetailsURL:  github.String(run.DetailsURL),
+				ExternalID:  github.String(run.ExternalID),
+				Status:      github.String(run.Status),
+				Conclusion:  optionalString(run.Conclusion),
+				CompletedAt: completedAt,
+				Output:      output,
+			})
+			if err != nil {
+				return err
+			}
+			id = r.GetID()
+			continue
+		}
+		_, _, err := m.V3.Checks.UpdateCheckRun(context.TODO(), m.Owner, m.Repository, id, github.UpdateCheckRunOptions{
+			Name:        run.Name,
+			DetailsURL:  github.String(run.DetailsURL),
+			Status:      github.String(run.Status),
+			Conclusion:  optionalString(run.Conclusion),
+			CompletedAt: completedAt,
+			Output:      output,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRunOutput for a check run with the given batch of annotations.
+func checkRunOutput(run CheckRun, annotations []Annotation) *github.CheckRunOutput {
+	if run.Title == "" && run.Summary == "" && run.Text == "" && len(annotations) == 0 {
+		return nil
+	}
+	output := &github.CheckRunOutput{
+		Title:   github.String(run.Title),
+		Summary: github.String(run.Summary),
+	}
+	if run.Text != "" {
+		output.Text = github.String(run.Text)
+	}
+	for _, a := range annotations {
+		output.Annotations = append(output.Annotations, &github.CheckRunAnnotation{
+			Path:            github.String(a.Path),
+			StartLine:       github.Int(a.StartLine),
+			EndLine:         github.Int(a.EndLine),
+			AnnotationLevel: github.String(a.Level),
+			Title:           optionalString(a.Title),
+			Message:         github.String(a.Message),
+		})
+	}
+	return output
+}
+
+// CreateReview submits a review on the given commit of a pull request. The request is built
+// by hand since the client does not support line and side for review comments.
+func (m *GithubClient) CreateReview(prNumber, commitRef string, review Review) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	body := struct {
+		CommitID string          `json:"commit_id"`
+		Event    string          `json:"event"`
+		Body     string          `json:"body,omitempty"`
+		Comments []ReviewComment `json:"comments,omitempty"`
+	}{
+		CommitID: commitRef,
+		Event:    review.Event,
+		Body:     review.Body,
+		Comments: review.Comments,
+	}
+
+	u := fmt.Sprintf("repos/%s/%s/pulls/%d/reviews", m.Owner, m.Repository, pr)
+	req, err := m.V3.NewRequest(http.MethodPost, u, body)
+	if err != nil {
+		return err
+	}
+	_, err = m.V3.Do(context.TODO(), req, nil)
+	return err
+}
+
+// MergePullRequest if the head of the pull request is still at the given commit, and
+// returns the SHA of the merge commit. The head branch is deleted afterwards if requested
+// and it belongs to the same repository.
+func (m *GithubClient) MergePullRequest(prNumber, commitRef string, merge Merge) (string, error) {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	p, _, err := m.V3.PullRequests.Get(context.TODO(), m.Owner, m.Repository, pr)
+	if err != nil {
+		return "", err
+	}
+	if p.GetMerged() {
+		return "", errors.New("pull request has already been merged")
+	}
+	if p.GetState() != "open" {
+		return "", fmt.Errorf("pull request is %s", p.GetState())
+	}
+	if sha := p.GetHead().GetSHA(); sha != commitRef {
+		return "", fmt.Errorf("head of the pull request has moved from %s to %s", commitRef, sha)
+	}
+	// Mergeable is not set while Github is still computing it, in which case we let the merge decide.
+	if p.Mergeable != nil && !p.GetMergeable() {
+		return "", fmt.Errorf("pull request is not mergeable (state: %s)", p.GetMergeableState())
+	}
+
+	result, response, err := m.V3.PullRequests.Merge(context.TODO(), m.Owner, m.Repository, pr, merge.CommitMessage, &github.PullRequestOptions{
+		CommitTitle: merge.CommitTitle,
+		SHA:         commitRef,
+		MergeMethod: merge.Method,
+	})
+	if err != nil {
+		if response != nil {
+			switch response.StatusCode {
+			case http.StatusMethodNotAllowed:
+				return "", fmt.Errorf("pull request is not mergeable: %s", err)
+			case http.StatusConflict:
+				return "", fmt.Errorf("head of the pull request has moved: %s", err)
+			}
+		}
+		return "", err
+	}
+
+	if merge.DeleteBranch && p.GetHead().GetRepo().GetFullName() == p.GetBase().GetRepo().GetFullName() {
+		_, err := m.V3.Git.DeleteRef(context.TODO(), m.Owner, m.Repository, "heads/"+p.GetHead().GetRef())
+		if err != nil {
+			return result.GetSHA(), fmt.Errorf("failed to delete branch: %s", err)
+		}
+	}
+	return result.GetSHA(), nil
+}
+
+// EnablePullRequestAutoMergeInput is an autogenerated input type of EnablePullRequestAutoMerge
+// (not yet supported by the githubv4 package).
+type EnablePullRequestAutoMergeInput struct {
+	PullRequestID   githubv4.ID                      `json:"pullRequestId"`
+	MergeMethod     *githubv4.PullRequestMergeMethod `json:"mergeMethod,omitempty"`
+	CommitHeadline  *githubv4.String                 `json:"commitHeadline,omitempty"`
+	CommitBody      *githubv4.String                 `json:"commitBody,omitempty"`
+	ExpectedHeadOid *githubv4.GitObjectID            `json:"expectedHeadOid,omitempty"`
+}
+
+// EnqueuePullRequestInput is an autogenerated input type of EnqueuePullRequest
+// (not yet supported by the githubv4 package).
+type EnqueuePullRequestInput struct {
+	PullRequestID   githubv4.ID           `json:"pullRequestId"`
+	ExpectedHeadOid *githubv4.GitObjectID `json:"expectedHeadOid,omitempty"`
+}
+
+// EnableAutoMerge for a pull request (by ID), so that it is merged by Github once all
+// requirements are met. Fails if the head is no longer at the given commit.
+func (m *GithubClient) EnableAutoMerge(pullRequestID, commitRef string, merge Merge) error {
+	var mutation struct {
+		EnablePullRequestAutoMerge struct {
+			ClientMutationID string
+		} `graphql:"enablePullRequestAutoMerge(input:$input)"`
+	}
+
+	input := EnablePullRequestAutoMergeInput{
+		PullRequestID:   githubv4.ID(pullRequestID),
+		ExpectedHeadOid: githubv4.NewGitObjectID(githubv4.GitObjectID(commitRef)),
+	}
+	if merge.Method != "" {
+		method := githubv4.PullRequestMergeMethod(strings.ToUpper(merge.Method))
+		input.MergeMethod = &method
+	}
+	if merge.CommitTitle != "" {
+		input.CommitHeadline = githubv4.NewString(githubv4.String(merge.CommitTitle))
+	}
+	if merge.CommitMessage != "" {
+		input.CommitBody = githubv4.NewString(githubv4.String(merge.CommitMessage))
+	}
+	return m.V4.Mutate(context.TODO(), &mutation, input, nil)
+}
+
+// EnqueuePullRequest (by ID) in the merge queue of its base branch. Fails if the head is
+// no longer at the given commit.
+func (m *GithubClient) EnqueuePullRequest(pullRequestID, commitRef string) error {
+	var mutation struct {
+		EnqueuePullRequest struct {
+			ClientMutationID string
+		} `graphql:"enqueuePullRequest(input:$input)"`
+	}
+
+	input := EnqueuePullRequestInput{
+		PullRequestID:   githubv4.ID(pullRequestID),
+		ExpectedHeadOid: githubv4.NewGitObjectID(githubv4.GitObjectID(commitRef)),
+	}
+	return m.V4.Mutate(context.TODO(), &mutation, input, nil)
+}
+
 func (m *GithubClient) DeletePreviousComments(prNumber string) error {
 	pr, err := strconv.Atoi(prNumber)
 	if err != nil {
@@ -406,6 +1036,202 @@ func (m *GithubClient) DeletePreviousComments(prNumber string) error {
 	return nil
 }
 
+// newHTTPClient returns the (unauthenticated) HTTP client used for all
+// requests to the Github APIs.
+func newHTTPClient(s *Source) *http.Client {
+	transport := http.DefaultTransport
+
+	// Skip SSL verification for self-signed certificates
+	// source: https://github.com/google/go-github/pull/598#issuecomment-333039238
+	if s.SkipSSLVerification {
+		transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+
+	// Retry requests that are rate limited, unless disabled.
+	if s.MaxRetries >= 0 {
+		maxRetries, maxWait := defaultMaxRetries, defaultMaxRetryWait
+		if s.MaxRetries > 0 {
+			maxRetries = s.MaxRetries
+		}
+		if d, err := time.ParseDuration(s.MaxRetryWait); err == nil {
+			maxWait = d
+		}
+		transport = &RetryTransport{
+			Base:       transport,
+			MaxRetries: maxRetries,
+			MaxWait:    maxWait,
+			MinBackoff: defaultMinBackoff,
+		}
+	}
+
+	// Make conditional requests using the responses cached on disk, if enabled.
+	if s.HTTPCache || s.HTTPCacheDir != "" {
+		dir := s.HTTPCacheDir
+		if dir == "" {
+			dir = defaultCacheDir(s)
+		}
+		pruneCache(dir, defaultCacheMaxAge)
+		transport = &CacheTransport{Base: transport, Dir: dir}
+	}
+	return &http.Client{Transport: transport}
+}
+
+// AddLabels to a pull request. Labels that do not exist in the repository are created
+// if createMissing is true, otherwise an error is returned.
+func (m *GithubClient) AddLabels(prNumber string, labels []string, createMissing bool) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	existing := make(map[string]bool)
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		result, response, err := m.V3.Issues.ListLabels(context.TODO(), m.Owner, m.Repository, opt)
+		if err != nil {
+			return err
+		}
+		for _, l := range result {
+			existing[strings.ToLower(l.GetName())] = true
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		opt.Page = response.NextPage
+	}
+
+	for _, l := range labels {
+		if existing[strings.ToLower(l)] {
+			continue
+		}
+		if !createMissing {
+			return fmt.Errorf("label '%s' does not exist", l)
+		}
+		_, _, err := m.V3.Issues.CreateLabel(context.TODO(), m.Owner, m.Repository, &github.Label{
+			Name:  github.String(l),
+			Color: github.String(defaultLabelColor),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create label '%s': %s", l, err)
+		}
+		existing[strings.ToLower(l)] = true
+	}
+
+	_, _, err = m.V3.Issues.AddLabelsToIssue(context.TODO(), m.Owner, m.Repository, pr, labels)
+	return err
+}
+
+// RemoveLabels from a pull request. Labels that are not set on the pull request are ignored.
+func (m *GithubClient) RemoveLabels(prNumber string, labels []string) error {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+
+	for _, l := range labels {
+		// The label is part of the path, and is not escaped by Issues.RemoveLabelForIssue.
+		u := fmt.Sprintf("repos/%s/%s/issues/%d/labels/%s", m.Owner, m.Repository, pr, url.PathEscape(l))
+		req, err := m.V3.NewRequest(http.MethodDelete, u, nil)
+		if err != nil {
+			return err
+		}
+		response, err := m.V3.Do(context.TODO(), req, nil)
+		if err != nil {
+			if response != nil && response.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return fmt.Errorf("failed to remove label '%s': %s", l, err)
+		}
+	}
+	return nil
+}
+
+// GetComment on a pull request or issue by its ID (not supported by V4 API). Comment IDs are
+// unique within the repository, so the pull request number is not used.
+func (m *GithubClient) GetComment(prNumber, commentID string) (*CommentObject, error) {
+	id, err := strconv.ParseInt(commentID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert comment id to int: %s", err)
+	}
+
+	c, _, err := m.V3.Issues.GetComment(context.TODO(), m.Owner, m.Repository, id)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &CommentObject{
+		DatabaseId:        c.GetID(),
+		Body:              c.GetBody(),
+		CreatedAt:         c.GetCreatedAt(),
+		AuthorAssociation: CommentAuthorAssociation(strings.ToUpper(c.GetAuthorAssociation())),
+	}
+	comment.Author.Login = c.GetUser().GetLogin()
+	return comment, nil
+}
+
+// ListTeamMembers returns the logins of all members of a team in an organization.
+func (m *GithubClient) ListTeamMembers(org, team string) ([]string, error) {
+	var query struct {
+		Organization struct {
+			Team *struct {
+				Members struct {
+					Nodes []struct {
+						Login string
+					}
+					PageInfo struct {
+						EndCursor   githubv4.String
+						HasNextPage bool
+					}
+				} `graphql:"members(first:$membersFirst,after:$membersCursor)"`
+			} `graphql:"team(slug:$teamSlug)"`
+		} `graphql:"organization(login:$organizationLogin)"`
+	}
+
+	vars := map[string]interface{}{
+		"organizationLogin": githubv4.String(org),
+		"teamSlug":          githubv4.String(team),
+		"membersFirst":      githubv4.Int(100),
+		"membersCursor":     (*githubv4.String)(nil),
+	}
+
+	var members []string
+	for {
+		if err := m.V4.Query(context.TODO(), &query, vars); err != nil {
+			return nil, err
+		}
+		if query.Organization.Team == nil {
+			return nil, fmt.Errorf("team '%s/%s' does not exist", org, team)
+		}
+		for _, n := range query.Organization.Team.Members.Nodes {
+			members = append(members, n.Login)
+		}
+		if !query.Organization.Team.Members.PageInfo.HasNextPage {
+			break
+		}
+		vars["membersCursor"] = query.Organization.Team.Members.PageInfo.EndCursor
+	}
+	return members, nil
+}
+
+// buildURL returns the URL of the current Concourse build.
+func buildURL() string {
+	return strings.Join([]string{os.Getenv("ATC_EXTERNAL_URL"), "builds", os.Getenv("BUILD_ID")}, "/")
+}
+
+// commentMarker returns the hidden marker used to identify comments by key.
+func commentMarker(key string) string {
+	return fmt.Sprintf("<!-- github-pr-resource: %s -->", key)
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return github.String(s)
+}
+
 func parseRepository(s string) (string, string, error) {
 	parts := strings.Split(s, "/")
 	if len(parts) != 2 {
@@ -413,3 +1239,11 @@ func parseRepository(s string) (string, string, error) {
 	}
 	return parts[0], parts[1], nil
 }
+
+func parseTeam(s string) (string, string, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("malformed team (expected org/team-slug): %s", s)
+	}
+	return parts[0], parts[1], nil
+}
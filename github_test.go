@@ -0,0 +1,785 @@
+package resource_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+func TestGithubClientUpdateCheckRun(t *testing.T) {
+	tests := []struct {
+		description     string
+		existing        string
+		status          string
+		annotations     int
+		expectedBatches []string
+	}{
+		{
+			description:     "creates a check run without annotations",
+			existing:        `{"total_count":0,"check_runs":[]}`,
+			annotations:     0,
+			expectedBatches: []string{"POST 0"},
+		},
+		{
+			description:     "creates a check run and adds annotations in batches",
+			existing:        `{"total_count":0,"check_runs":[]}`,
+			annotations:     120,
+			expectedBatches: []string{"POST 50", "PATCH 50", "PATCH 20"},
+		},
+		{
+			description:     "updates an existing check run for the same build",
+			existing:        `{"total_count":2,"check_runs":[{"id":3,"external_id":"other"},{"id":7,"external_id":"build"}]}`,
+			annotations:     60,
+			expectedBatches: []string{"PATCH 50", "PATCH 10"},
+		},
+		{
+			description:     "only sends annotations when the check run is completed",
+			existing:        `{"total_count":1,"check_runs":[{"id":7,"external_id":"build"}]}`,
+			status:          "in_progress",
+			annotations:     60,
+			expectedBatches: []string{"PATCH 0"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var batches []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/repos/itsdalmo/test-repository/commits/oid/check-runs":
+					assert.Equal(t, "lint", r.URL.Query().Get("check_name"))
+					fmt.Fprint(w, tc.existing)
+				case r.Method == http.MethodPost && r.URL.Path == "/repos/itsdalmo/test-repository/check-runs",
+					r.Method == http.MethodPatch && r.URL.Path == "/repos/itsdalmo/test-repository/check-runs/7":
+					var body struct {
+						Output struct {
+							Annotations []resource.Annotation `json:"annotations"`
+						} `json:"output"`
+					}
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					batches = append(batches, fmt.Sprintf("%s %d", r.Method, len(body.Output.Annotations)))
+					fmt.Fprint(w, `{"id":7}`)
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			source := resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				V3Endpoint:  server.URL + "/",
+				V4Endpoint:  server.URL + "/graphql",
+			}
+			client, err := resource.NewGithubClient(&source, nil)
+			require.NoError(t, err)
+
+			run := resource.CheckRun{
+				Name:       "lint",
+				ExternalID: "build",
+				Status:     "completed",
+				Conclusion: "failure",
+				Title:      "lint",
+				Summary:    "lint",
+			}
+			if tc.status != "" {
+				run.Status, run.Conclusion = tc.status, ""
+			}
+			for i := 0; i < tc.annotations; i++ {
+				run.Annotations = append(run.Annotations, resource.Annotation{
+					Path: "main.go", StartLine: i + 1, EndLine: i + 1, Level: "warning", Message: "issue",
+				})
+			}
+
+			if assert.NoError(t, client.UpdateCheckRun("oid", run)) {
+				assert.Equal(t, tc.expectedBatches, batches)
+			}
+		})
+	}
+}
+
+func TestGithubClientUpdateComment(t *testing.T) {
+	tests := []struct {
+		description     string
+		comments        string
+		expectedRequest string
+	}{
+		{
+			description:     "creates a comment when none has the key",
+			comments:        `[{"databaseId":1,"body":"hello","author":{"login":"bot"}}]`,
+			expectedRequest: "POST /repos/itsdalmo/test-repository/issues/1/comments",
+		},
+		{
+			description: "updates the comment with the same key",
+			comments: `[
+				{"databaseId":1,"body":"plan\n\n<!-- github-pr-resource: plan -->","author":{"login":"bot"}},
+				{"databaseId":2,"body":"lint\n\n<!-- github-pr-resource: lint -->","author":{"login":"bot"}}
+			]`,
+			expectedRequest: "PATCH /repos/itsdalmo/test-repository/issues/comments/1",
+		},
+		{
+			description:     "ignores comments by other users",
+			comments:        `[{"databaseId":1,"body":"> <!-- github-pr-resource: plan -->","author":{"login":"someone"}}]`,
+			expectedRequest: "POST /repos/itsdalmo/test-repository/issues/1/comments",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/graphql" {
+					fmt.Fprintf(w, `{"data":{"viewer":{"login":"bot"},"repository":{"pullRequest":{"comments":{"nodes":%s,"pageInfo":{"hasNextPage":false}}}}}}`, tc.comments)
+					return
+				}
+				var body struct {
+					Body string `json:"body"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, "new plan\n\n<!-- github-pr-resource: plan -->", body.Body)
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				fmt.Fprint(w, `{"id":1}`)
+			}))
+			defer server.Close()
+
+			source := resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				V3Endpoint:  server.URL + "/",
+				V4Endpoint:  server.URL + "/graphql",
+			}
+			client, err := resource.NewGithubClient(&source, nil)
+			require.NoError(t, err)
+
+			if assert.NoError(t, client.UpdateComment("1", "plan", "new plan")) {
+				assert.Equal(t, []string{tc.expectedRequest}, requests)
+			}
+		})
+	}
+}
+
+func TestGithubClientLabels(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/itsdalmo/test-repository/labels":
+			fmt.Fprint(w, `[{"name":"CI:Passed"}]`)
+		case r.Method == http.MethodDelete && r.URL.Path == "/repos/itsdalmo/test-repository/issues/1/labels/missing":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Label does not exist"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/itsdalmo/test-repository/labels":
+			fmt.Fprint(w, `{"name":"deploy:staging"}`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	}
+	client, err := resource.NewGithubClient(&source, nil)
+	require.NoError(t, err)
+
+	t.Run("fails on missing labels", func(t *testing.T) {
+		requests = nil
+		err := client.AddLabels("1", []string{"ci:passed", "deploy:staging"}, false)
+		assert.EqualError(t, err, "label 'deploy:staging' does not exist")
+		assert.Equal(t, []string{"GET /repos/itsdalmo/test-repository/labels"}, requests)
+	})
+
+	t.Run("creates missing labels", func(t *testing.T) {
+		requests = nil
+		assert.NoError(t, client.AddLabels("1", []string{"ci:passed", "deploy:staging"}, true))
+		assert.Equal(t, []string{
+			"GET /repos/itsdalmo/test-repository/labels",
+			"POST /repos/itsdalmo/test-repository/labels",
+			"POST /repos/itsdalmo/test-repository/issues/1/labels",
+		}, requests)
+	})
+
+	t.Run("ignores labels that are not set when removing", func(t *testing.T) {
+		requests = nil
+		assert.NoError(t, client.RemoveLabels("1", []string{"missing", "ci:passed"}))
+		assert.Equal(t, []string{
+			"DELETE /repos/itsdalmo/test-repository/issues/1/labels/missing",
+			"DELETE /repos/itsdalmo/test-repository/issues/1/labels/ci:passed",
+		}, requests)
+	})
+
+	t.Run("escapes labels when removing", func(t *testing.T) {
+		requests = nil
+		assert.NoError(t, client.RemoveLabels("1", []string{"area/backend", "priority #1?"}))
+		assert.Equal(t, []string{
+			"DELETE /repos/itsdalmo/test-repository/issues/1/labels/area%2Fbackend",
+			"DELETE /repos/itsdalmo/test-repository/issues/1/labels/priority%20%231%3F",
+		}, requests)
+	})
+}
+
+func TestGithubClientCreateReview(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST /repos/itsdalmo/test-repository/pulls/1/reviews", r.Method+" "+r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		fmt.Fprint(w, `{"id":1}`)
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	}
+	client, err := resource.NewGithubClient(&source, nil)
+	require.NoError(t, err)
+
+	err = client.CreateReview("1", "oid", resource.Review{
+		Event: "COMMENT",
+		Body:  "Looks fine",
+		Comments: []resource.ReviewComment{
+			{Path: "main.go", Line: 10, Side: "RIGHT", Body: "nit"},
+		},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]interface{}{
+			"commit_id": "oid",
+			"event":     "COMMENT",
+			"body":      "Looks fine",
+			"comments": []interface{}{
+				map[string]interface{}{"path": "main.go", "line": float64(10), "side": "RIGHT", "body": "nit"},
+			},
+		}, body)
+	}
+}
+
+func TestGithubClientMergePullRequest(t *testing.T) {
+	tests := []struct {
+		description      string
+		pull             string
+		mergeStatus      int
+		expectedSHA      string
+		expectedRequests []string
+		expectedError    string
+	}{
+		{
+			description: "merges and deletes the branch",
+			pull:        `{"state":"open","mergeable":true,"head":{"sha":"oid","ref":"feature","repo":{"full_name":"itsdalmo/test-repository"}},"base":{"repo":{"full_name":"itsdalmo/test-repository"}}}`,
+			mergeStatus: http.StatusOK,
+			expectedSHA: "merged",
+			expectedRequests: []string{
+				"GET /repos/itsdalmo/test-repository/pulls/1",
+				"PUT /repos/itsdalmo/test-repository/pulls/1/merge",
+				"DELETE /repos/itsdalmo/test-repository/git/refs/heads/feature",
+			},
+		},
+		{
+			description: "does not delete branches in forks",
+			pull:        `{"state":"open","head":{"sha":"oid","ref":"feature","repo":{"full_name":"fork/test-repository"}},"base":{"repo":{"full_name":"itsdalmo/test-repository"}}}`,
+			mergeStatus: http.StatusOK,
+			expectedSHA: "merged",
+			expectedRequests: []string{
+				"GET /repos/itsdalmo/test-repository/pulls/1",
+				"PUT /repos/itsdalmo/test-repository/pulls/1/merge",
+			},
+		},
+		{
+			description:      "fails if the head has moved",
+			pull:             `{"state":"open","mergeable":true,"head":{"sha":"newer"}}`,
+			expectedRequests: []string{"GET /repos/itsdalmo/test-repository/pulls/1"},
+			expectedError:    "head of the pull request has moved from oid to newer",
+		},
+		{
+			description:      "fails if the pull request is not mergeable",
+			pull:             `{"state":"open","mergeable":false,"mergeable_state":"dirty","head":{"sha":"oid"}}`,
+			expectedRequests: []string{"GET /repos/itsdalmo/test-repository/pulls/1"},
+			expectedError:    "pull request is not mergeable (state: dirty)",
+		},
+		{
+			description: "fails if the merge is rejected",
+			pull:        `{"state":"open","head":{"sha":"oid"}}`,
+			mergeStatus: http.StatusMethodNotAllowed,
+			expectedRequests: []string{
+				"GET /repos/itsdalmo/test-repository/pulls/1",
+				"PUT /repos/itsdalmo/test-repository/pulls/1/merge",
+			},
+			expectedError: "pull request is not mergeable",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				switch r.Method {
+				case http.MethodGet:
+					fmt.Fprint(w, tc.pull)
+				case http.MethodPut:
+					var body map[string]interface{}
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					assert.Equal(t, map[string]interface{}{
+						"commit_title":   "title",
+						"commit_message": "message",
+						"sha":            "oid",
+						"merge_method":   "squash",
+					}, body)
+					w.WriteHeader(tc.mergeStatus)
+					fmt.Fprint(w, `{"sha":"merged","merged":true,"message":"Base branch was modified"}`)
+				case http.MethodDelete:
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			defer server.Close()
+
+			source := resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				V3Endpoint:  server.URL + "/",
+				V4Endpoint:  server.URL + "/graphql",
+			}
+			client, err := resource.NewGithubClient(&source, nil)
+			require.NoError(t, err)
+
+			sha, err := client.MergePullRequest("1", "oid", resource.Merge{
+				Method:        "squash",
+				CommitTitle:   "title",
+				CommitMessage: "message",
+				DeleteBranch:  true,
+			})
+			if tc.expectedError != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.expectedError)
+				}
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tc.expectedSHA, sha)
+			}
+			assert.Equal(t, tc.expectedRequests, requests)
+		})
+	}
+}
+
+func TestGithubClientMergeMutations(t *testing.T) {
+	var request struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	}
+	client, err := resource.NewGithubClient(&source, nil)
+	require.NoError(t, err)
+
+	t.Run("enables auto-merge", func(t *testing.T) {
+		err := client.EnableAutoMerge("PR_1", "oid", resource.Merge{Method: "squash", CommitTitle: "title"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "mutation($input:EnablePullRequestAutoMergeInput!){enablePullRequestAutoMerge(input:$input){clientMutationId}}", request.Query)
+			assert.Equal(t, map[string]interface{}{
+				"input": map[string]interface{}{
+					"pullRequestId":   "PR_1",
+					"mergeMethod":     "SQUASH",
+					"commitHeadline":  "title",
+					"expectedHeadOid": "oid",
+				},
+			}, request.Variables)
+		}
+	})
+
+	t.Run("adds to the merge queue", func(t *testing.T) {
+		err := client.EnqueuePullRequest("PR_1", "oid")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "mutation($input:EnqueuePullRequestInput!){enqueuePullRequest(input:$input){clientMutationId}}", request.Query)
+			assert.Equal(t, map[string]interface{}{
+				"input": map[string]interface{}{
+					"pullRequestId":   "PR_1",
+					"expectedHeadOid": "oid",
+				},
+			}, request.Variables)
+		}
+	})
+}
+
+func TestGithubClientListPullRequestCommits(t *testing.T) {
+	pages := []string{
+		`{"data":{"repository":{"pullRequest":{"commits":{"edges":[
+			{"node":{"commit":{"oid":"c","committedDate":"2019-01-03T00:00:00Z"}}},
+			{"node":{"commit":{"oid":"d","committedDate":"2019-01-04T00:00:00Z"}}}
+		],"pageInfo":{"startCursor":"cursor","hasPreviousPage":true}}}}}}`,
+		`{"data":{"repository":{"pullRequest":{"commits":{"edges":[
+			{"node":{"commit":{"oid":"a","committedDate":"2019-01-01T00:00:00Z"}}},
+			{"node":{"commit":{"oid":"b","committedDate":"2019-01-02T12:00:00Z"}}}
+		],"pageInfo":{"startCursor":"first","hasPreviousPage":true}}}}}}`,
+	}
+
+	var cursors []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		cursors = append(cursors, request.Variables["commitsCursor"])
+		fmt.Fprint(w, pages[len(cursors)-1])
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	}
+	client, err := resource.NewGithubClient(&source, nil)
+	require.NoError(t, err)
+
+	commits, err := client.ListPullRequestCommits(1, "a")
+	if assert.NoError(t, err) {
+		var oids []string
+		for _, c := range commits {
+			oids = append(oids, c.OID)
+		}
+		assert.Equal(t, []string{"b", "c", "d"}, oids)
+	}
+	assert.Equal(t, []interface{}{nil, "cursor"}, cursors)
+}
+
+func TestGithubClientListOpenPullRequestsPushedTime(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query string `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		query = request.Query
+
+		fmt.Fprint(w, `{"data":{"repository":{"pullRequests":{"edges":[
+			{"node":{"number":1,"updatedAt":"2019-01-04T00:00:00Z",
+				"commits":{"edges":[{"node":{"commit":{"oid":"a","committedDate":"2019-01-01T00:00:00Z"}}}]},
+				"pushes":{"nodes":[
+					{"__typename":"PullRequestCommit","commit":{"committedDate":"2019-01-02T00:00:00Z"}},
+					{"__typename":"HeadRefForcePushedEvent","createdAt":"2019-01-03T00:00:00Z","afterCommit":{"oid":"a"}}
+				],"pageInfo":{"hasPreviousPage":false}}}},
+			{"node":{"number":2,"updatedAt":"2019-01-04T00:00:00Z",
+				"commits":{"edges":[{"node":{"commit":{"oid":"b","committedDate":"2019-01-01T00:00:00Z"}}}]},
+				"pushes":{"nodes":[
+					{"__typename":"HeadRefForcePushedEvent","createdAt":"2019-01-03T00:00:00Z","afterCommit":{"oid":"other"}},
+					{"__typename":"PullRequestCommit","commit":{"committedDate":"2019-01-01T00:00:00Z"}}
+				],"pageInfo":{"hasPreviousPage":true}}}},
+			{"node":{"number":3,"updatedAt":"2019-01-04T00:00:00Z",
+				"commits":{"edges":[{"node":{"commit":{"oid":"c","committedDate":"2019-01-01T00:00:00Z"}}}]},
+				"pushes":{"nodes":[
+					{"__typename":"HeadRefForcePushedEvent","createdAt":"2019-01-03T00:00:00Z","afterCommit":{"oid":"c"}},
+					{"__typename":"PullRequestCommit","commit":{"committedDate":"2019-01-02T00:00:00Z"}}
+				],"pageInfo":{"hasPreviousPage":false}}}}
+		],"pageInfo":{"hasNextPage":false}}}}}`)
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	}
+	client, err := resource.NewGithubClient(&source, nil)
+	require.NoError(t, err)
+
+	pulls, err := client.ListOpenPullRequests()
+	if assert.NoError(t, err) && assert.Len(t, pulls, 3) {
+		// Only the latest push is the push of the tip.
+		assert.Equal(t, time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC), pulls[0].PushedTime.UTC())
+		assert.True(t, pulls[1].PushedTime.IsZero())
+		assert.True(t, pulls[2].PushedTime.IsZero())
+		for _, p := range pulls {
+			assert.Equal(t, time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC), p.PushedAfter.UTC())
+			assert.Equal(t, time.Date(2019, 1, 4, 0, 0, 0, 0, time.UTC), p.UpdatedAt.UTC())
+		}
+		assert.True(t, pulls[0].PushesListed)
+		assert.False(t, pulls[1].PushesListed)
+		assert.True(t, pulls[2].PushesListed)
+	}
+	assert.NotContains(t, query, "pushedDate")
+}
+
+func TestGithubClientListOpenPullRequestsOptionalFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"repository":{"pullRequests":{"edges":[
+			{"node":{"number":1,
+				"commits":{"edges":[{"node":{"commit":{"oid":"a","committedDate":"2019-01-01T00:00:00Z"}}}]},
+				"mergeQueueEntry":{"headCommit":{"oid":"group","committedDate":"2019-01-02T00:00:00Z"}},
+				"baseRef":{"target":{"oid":"base","committedDate":"2019-01-03T00:00:00Z"}}}},
+			{"node":{"number":2,
+				"commits":{"edges":[{"node":{"commit":{"oid":"b","committedDate":"2019-01-01T00:00:00Z"}}}]},
+				"mergeQueueEntry":null,
+				"baseRef":null}}
+		],"pageInfo":{"hasNextPage":false}}}}}`)
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:      "itsdalmo/test-repository",
+		AccessToken:     "oauthtoken",
+		V3Endpoint:      server.URL + "/",
+		V4Endpoint:      server.URL + "/graphql",
+		MergeQueue:      true,
+		TrackBaseBranch: true,
+	}
+	client, err := resource.NewGithubClient(&source, nil)
+	require.NoError(t, err)
+
+	pulls, err := client.ListOpenPullRequests()
+	if assert.NoError(t, err) && assert.Len(t, pulls, 2) {
+		assert.Equal(t, "group", pulls[0].MergeGroup.OID)
+		assert.Equal(t, "base", pulls[0].BaseTip.OID)
+		assert.Equal(t, time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC), pulls[0].BaseTip.CommittedDate.UTC())
+		assert.Empty(t, pulls[1].MergeGroup.OID)
+		assert.Empty(t, pulls[1].BaseTip.OID)
+	}
+}
+
+func TestGithubClientListOpenPullRequestsStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"repository":{"pullRequests":{"edges":[
+			{"node":{"number":1,
+				"commits":{"edges":[{"node":{"commit":{"oid":"a","statusCheckRollup":{"contexts":{"nodes":[
+					{"context":"concourse-ci/unit-test","state":"SUCCESS","createdAt":"2019-01-02T00:00:00Z"},
+					{"context":"concourse-ci/lint","state":"EXPECTED","createdAt":"2019-01-02T00:00:00Z"},
+					{"name":"build","status":"COMPLETED","conclusion":"FAILURE","startedAt":"2019-01-02T00:00:00Z","completedAt":"2019-01-03T00:00:00Z"},
+					{"name":"deploy","status":"IN_PROGRESS","conclusion":null,"startedAt":"2019-01-04T00:00:00Z","completedAt":null}
+				]}}}}}]}}},
+			{"node":{"number":2,
+				"commits":{"edges":[{"node":{"commit":{"oid":"b","statusCheckRollup":null}}}]}}}
+		],"pageInfo":{"hasNextPage":false}}}}}`)
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:       "itsdalmo/test-repository",
+		AccessToken:      "oauthtoken",
+		V3Endpoint:       server.URL + "/",
+		V4Endpoint:       server.URL + "/graphql",
+		RequiredStatuses: []resource.RequiredStatus{{Context: "concourse-ci/unit-test"}},
+	}
+	client, err := resource.NewGithubClient(&source, nil)
+	require.NoError(t, err)
+
+	pulls, err := client.ListOpenPullRequests()
+	if assert.NoError(t, err) && assert.Len(t, pulls, 2) {
+		assert.Equal(t, []resource.Status{
+			{Context: "concourse-ci/unit-test", State: "success", UpdatedAt: time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)},
+			{Context: "concourse-ci/lint", State: "pending", UpdatedAt: time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)},
+			{Context: "build", State: "failure", UpdatedAt: time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC)},
+			{Context: "deploy", State: "pending", UpdatedAt: time.Date(2019, 1, 4, 0, 0, 0, 0, time.UTC)},
+		}, pulls[0].Statuses)
+		assert.Empty(t, pulls[1].Statuses)
+	}
+}
+
+func TestGithubClientListOpenPullRequestsLabelEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"repository":{"pullRequests":{"edges":[
+			{"node":{"number":1,
+				"commits":{"edges":[{"node":{"commit":{"oid":"a"}}}]},
+				"labelEvents":{"nodes":[
+					{"__typename":"LabeledEvent","createdAt":"2019-01-01T00:00:00Z","label":{"name":"bug"}},
+					{"__typename":"UnlabeledEvent","createdAt":"2019-01-02T00:00:00Z","label":{"name":"wontfix"}}
+				]}}}
+		],"pageInfo":{"hasNextPage":false}}}}}`)
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+		Labels:      []string{"bug"},
+	}
+	client, err := resource.NewGithubClient(&source, nil)
+	require.NoError(t, err)
+
+	pulls, err := client.ListOpenPullRequests()
+	if assert.NoError(t, err) && assert.Len(t, pulls, 1) {
+		assert.Equal(t, []resource.LabelEvent{
+			{Name: "bug", Added: true, CreatedAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Name: "wontfix", Added: false, CreatedAt: time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)},
+		}, pulls[0].LabelEvents)
+	}
+}
+
+func TestGithubClientCompareCommits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/itsdalmo/test-repository/compare/old...new":
+			fmt.Fprint(w, `{"files":[{"filename":"terraform/main.tf"},{"filename":"README.md"}]}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	}
+	client, err := resource.NewGithubClient(&source, nil)
+	require.NoError(t, err)
+
+	files, err := client.ListModifiedFilesBetween("old", "new")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"terraform/main.tf", "README.md"}, files)
+	}
+}
+
+func TestGithubClientGetPullRequestCommitBefore(t *testing.T) {
+	tests := []struct {
+		description string
+		activity    string
+		before      time.Time
+		expected    string
+	}{
+		{
+			description: "uses the latest push at or before the time",
+			activity: `[
+				{"before":"b","after":"c","timestamp":"2019-01-05T00:00:00Z","activity_type":"push"},
+				{"before":"a","after":"b","timestamp":"2019-01-04T00:00:00Z","activity_type":"push"},
+				{"before":"0000000000000000000000000000000000000000","after":"a","timestamp":"2019-01-03T00:00:00Z","activity_type":"branch_creation"}
+			]`,
+			before:   time.Date(2019, 1, 4, 12, 0, 0, 0, time.UTC),
+			expected: "b",
+		},
+		{
+			description: "uses the push rather than the commit date of old commits",
+			activity: `[
+				{"before":"b","after":"c","timestamp":"2019-01-05T00:00:00Z","activity_type":"push"},
+				{"before":"a","after":"b","timestamp":"2019-01-04T00:00:00Z","activity_type":"force_push"}
+			]`,
+			before:   time.Date(2019, 1, 4, 12, 0, 0, 0, time.UTC),
+			expected: "b",
+		},
+		{
+			description: "returns nothing if the branch was created after the time",
+			activity: `[
+				{"before":"a","after":"b","timestamp":"2019-01-04T00:00:00Z","activity_type":"push"},
+				{"before":"0000000000000000000000000000000000000000","after":"a","timestamp":"2019-01-03T00:00:00Z","activity_type":"branch_creation"}
+			]`,
+			before:   time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: "",
+		},
+		{
+			description: "falls back to the commit dates without activity",
+			activity:    `[]`,
+			before:      time.Date(2019, 1, 2, 12, 0, 0, 0, time.UTC),
+			expected:    "b",
+		},
+		{
+			description: "falls back to the commit dates if the activity cannot be listed",
+			before:      time.Date(2019, 1, 2, 12, 0, 0, 0, time.UTC),
+			expected:    "b",
+		},
+		{
+			description: "returns nothing if all commits are later",
+			before:      time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected:    "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/repos/itsdalmo/test-repository/pulls/1":
+					fmt.Fprint(w, `{"number":1,"head":{"ref":"feature","repo":{"name":"fork","owner":{"login":"someone"}}}}`)
+				case "/repos/someone/fork/activity":
+					assert.Equal(t, "refs/heads/feature", r.URL.Query().Get("ref"))
+					if tc.activity == "" {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					fmt.Fprint(w, tc.activity)
+				case "/graphql":
+					fmt.Fprint(w, `{"data":{"repository":{"pullRequest":{"commits":{"edges":[
+						{"node":{"commit":{"oid":"a","committedDate":"2019-01-01T00:00:00Z"}}},
+						{"node":{"commit":{"oid":"b","committedDate":"2019-01-02T00:00:00Z"}}},
+						{"node":{"commit":{"oid":"c","committedDate":"2019-01-03T00:00:00Z"}}}
+					],"pageInfo":{"startCursor":"first","hasPreviousPage":false}}}}}}`)
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			source := resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				V3Endpoint:  server.URL + "/",
+				V4Endpoint:  server.URL + "/graphql",
+				MaxRetries:  -1,
+			}
+			client, err := resource.NewGithubClient(&source, nil)
+			require.NoError(t, err)
+
+			sha, err := client.GetPullRequestCommitBefore(1, tc.before)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, sha)
+			}
+		})
+	}
+}
+
+func TestGithubClientListOpenPullRequestsFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"repository":{"pullRequests":{"edges":[
+			{"node":{"number":1,
+				"commits":{"edges":[{"node":{"commit":{"oid":"a"}}}]},
+				"files":{"nodes":[{"path":"README.md"},{"path":"terraform/main.tf"}],"pageInfo":{"hasNextPage":false}}}},
+			{"node":{"number":2,
+				"commits":{"edges":[{"node":{"commit":{"oid":"b"}}}]},
+				"files":{"nodes":[],"pageInfo":{"hasNextPage":false}}}},
+			{"node":{"number":3,
+				"commits":{"edges":[{"node":{"commit":{"oid":"c"}}}]},
+				"files":{"nodes":[{"path":"README.md"}],"pageInfo":{"hasNextPage":true}}}}
+		],"pageInfo":{"hasNextPage":false}}}}}`)
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+		Paths:       []string{"terraform/"},
+	}
+	client, err := resource.NewGithubClient(&source, nil)
+	require.NoError(t, err)
+
+	pulls, err := client.ListOpenPullRequests()
+	if assert.NoError(t, err) && assert.Len(t, pulls, 3) {
+		assert.Equal(t, []string{"README.md", "terraform/main.tf"}, pulls[0].Files)
+		assert.Equal(t, []string{}, pulls[1].Files)
+		assert.Nil(t, pulls[2].Files)
+	}
+}
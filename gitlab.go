@@ -0,0 +1,784 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/oauth2"
+)
+
+// defaultGitlabEndpoint is the API endpoint of gitlab.com.
+const defaultGitlabEndpoint = "https://gitlab.com/api/v4/"
+
+// gitlabDeveloperAccess is the access level of project members whose comments are authorised.
+// https://docs.gitlab.com/ee/api/members.html#valid-access-levels
+const gitlabDeveloperAccess = 30
+
+// GitlabClient for handling requests to the Gitlab (V4) API, where pull requests are merge
+// requests identified by their IID.
+type GitlabClient struct {
+	Project string
+	api     *restClient
+	source  Source
+}
+
+// NewGitlabClient creates a Gitlab client, which uses a token source for the
+// source configuration if tokenSource is nil.
+func NewGitlabClient(s *Source, tokenSource oauth2.TokenSource) (*GitlabClient, error) {
+	if parts := strings.Split(s.Repository, "/"); len(parts) < 2 {
+		return nil, errors.New("malformed repository")
+	}
+
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = defaultGitlabEndpoint
+	}
+	api, err := newRESTClient(s, tokenSource, endpoint, "per_page", 100)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitlabClient{
+		Project: s.Repository,
+		api:     api,
+		source:  *s,
+	}, nil
+}
+
+// gitlabMergeRequest represents a merge request.
+// https://docs.gitlab.com/ee/api/merge_requests.html
+type gitlabMergeRequest struct {
+	IID             int        `json:"iid"`
+	Title           string     `json:"title"`
+	State           string     `json:"state"`
+	WebURL          string     `json:"web_url"`
+	SourceBranch    string     `json:"source_branch"`
+	TargetBranch    string     `json:"target_branch"`
+	SourceProjectID int64      `json:"source_project_id"`
+	TargetProjectID int64      `json:"target_project_id"`
+	Draft           bool       `json:"draft"`
+	WorkInProgress  bool       `json:"work_in_progress"`
+	HasConflicts    bool       `json:"has_conflicts"`
+	MergeStatus     string     `json:"merge_status"`
+	SHA             string     `json:"sha"`
+	MergeCommitSHA  string     `json:"merge_commit_sha"`
+	SquashCommitSHA string     `json:"squash_commit_sha"`
+	Labels          []string   `json:"labels"`
+	Author          gitlabUser `json:"author"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// gitlabMergeRequestVersion represents a version of a merge request, which is created for
+// every push to it (latest first).
+type gitlabMergeRequestVersion struct {
+	HeadCommitSHA string    `json:"head_commit_sha"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// gitlabUser represents a user (e.g. the author of a merge request or note).
+type gitlabUser struct {
+	Username    string `json:"username"`
+	AccessLevel int    `json:"access_level"`
+}
+
+// gitlabCommit represents a commit.
+// https://docs.gitlab.com/ee/api/commits.html
+type gitlabCommit struct {
+	ID            string    `json:"id"`
+	Message       string    `json:"message"`
+	CommittedDate time.Time `json:"committed_date"`
+}
+
+// gitlabNote represents a comment on a merge request.
+// https://docs.gitlab.com/ee/api/notes.html
+type gitlabNote struct {
+	ID        int64      `json:"id"`
+	Body      string     `json:"body"`
+	System    bool       `json:"system"`
+	CreatedAt time.Time  `json:"created_at"`
+	Author    gitlabUser `json:"author"`
+}
+
+// gitlabDiff represents a file changed by a merge request or comparison.
+type gitlabDiff struct {
+	NewPath string `json:"new_path"`
+}
+
+// ListOpenPullRequests gets the last commit on all open merge requests. Details that are only
+// used by some of the source configuration (e.g. approvals) are requested when needed.
+func (m *GitlabClient) ListOpenPullRequests() ([]*PullRequest, error) {
+	// Merge requests that are filtered out by check anyway are not listed, to save the requests
+	// that are made for each of them.
+	query := url.Values{"state": {"opened"}}
+	if m.source.BaseBranch != "" {
+		query.Set("target_branch", m.source.BaseBranch)
+	}
+	if m.source.IgnoreDrafts {
+		query.Set("wip", "no")
+	}
+
+	var mrs []gitlabMergeRequest
+	var page []gitlabMergeRequest
+	err := m.api.list(m.projectPath("merge_requests"), query, &page, func() bool {
+		mrs = append(mrs, page...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Comments are authorised by the access level of their authors in the project.
+	var members map[string]bool
+	if m.source.TriggerPhrase != "" {
+		members = make(map[string]bool)
+		var page []gitlabUser
+		err := m.api.list(m.projectPath("members", "all"), nil, &page, func() bool {
+			for _, u := range page {
+				if u.AccessLevel >= gitlabDeveloperAccess {
+					members[u.Username] = true
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list project members: %s", err)
+		}
+	}
+
+	// The tips of the base branches are shared by the merge requests against them.
+	baseTips := make(map[string]CommitObject)
+	if m.source.TrackBaseBranch {
+		for _, mr := range mrs {
+			if _, ok := baseTips[mr.TargetBranch]; ok {
+				continue
+			}
+			var branch struct {
+				Commit gitlabCommit `json:"commit"`
+			}
+			if _, err := m.api.do(http.MethodGet, m.projectPath("repository", "branches", mr.TargetBranch), nil, nil, &branch); err != nil {
+				return nil, fmt.Errorf("failed to get base branch %s: %s", mr.TargetBranch, err)
+			}
+			baseTips[mr.TargetBranch] = branch.Commit.commitObject()
+		}
+	}
+
+	// Each merge request takes at least one more request, so they are made concurrently if
+	// specified by check_concurrency.
+	response := make([]*PullRequest, len(mrs))
+	errs := runConcurrently(len(mrs), m.source.CheckConcurrency, func(i int) error {
+		p, err := m.openPullRequest(mrs[i], members, baseTips)
+		response[i] = p
+		return err
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// openPullRequest returns an open merge request with the tip, and the details that are used
+// by the source configuration.
+func (m *GitlabClient) openPullRequest(mr gitlabMergeRequest, members map[string]bool, baseTips map[string]CommitObject) (*PullRequest, error) {
+	iid := strconv.Itoa(mr.IID)
+	p := &PullRequest{PullRequestObject: mr.pullRequestObject()}
+
+	var commit gitlabCommit
+	if _, err := m.api.do(http.MethodGet, m.projectPath("repository", "commits", mr.SHA), nil, nil, &commit); err != nil {
+		return nil, fmt.Errorf("failed to get tip of merge request %s: %s", iid, err)
+	}
+	p.Tip = commit.commitObject()
+
+	for _, l := range mr.Labels {
+		p.Labels = append(p.Labels, LabelObject{Name: l})
+	}
+
+	if m.source.RequiredReviewApprovals > 0 {
+		var approvals struct {
+			ApprovedBy []struct {
+				User gitlabUser `json:"user"`
+			} `json:"approved_by"`
+		}
+		if _, err := m.api.do(http.MethodGet, m.projectPath("merge_requests", iid, "approvals"), nil, nil, &approvals); err != nil {
+			return nil, fmt.Errorf("failed to get approvals of merge request %s: %s", iid, err)
+		}
+		p.ApprovedReviewCount = len(approvals.ApprovedBy)
+	}
+
+	if m.source.TriggerPhrase != "" {
+		var notes []gitlabNote
+		query := url.Values{"sort": {"desc"}, "order_by": {"created_at"}, "per_page": {"100"}}
+		if _, err := m.api.do(http.MethodGet, m.projectPath("merge_requests", iid, "notes"), query, nil, &notes); err != nil {
+			return nil, fmt.Errorf("failed to list notes of merge request %s: %s", iid, err)
+		}
+		for i := len(notes) - 1; i >= 0; i-- {
+			if notes[i].System {
+				continue
+			}
+			c := notes[i].commentObject()
+			if members[c.Author.Login] {
+				c.AuthorAssociation = CommentAuthorAssociationMember
+			}
+			p.Comments = append(p.Comments, c)
+		}
+	}
+
+	if len(m.source.Labels) > 0 || len(m.source.IgnoreLabels) > 0 {
+		var page []struct {
+			Action    string    `json:"action"`
+			CreatedAt time.Time `json:"created_at"`
+			Label     struct {
+				Name string `json:"name"`
+			} `json:"label"`
+		}
+		err := m.api.list(m.projectPath("merge_requests", iid, "resource_label_events"), nil, &page, func() bool {
+			for _, e := range page {
+				p.LabelEvents = append(p.LabelEvents, LabelEvent{Name: e.Label.Name, Added: e.Action == "add", CreatedAt: e.CreatedAt})
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list label events of merge request %s: %s", iid, err)
+		}
+	}
+
+	if len(m.source.RequiredStatuses) > 0 {
+		statuses, err := m.listStatuses(mr.SHA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list statuses of merge request %s: %s", iid, err)
+		}
+		p.Statuses = statuses
+	}
+
+	if m.source.TrackBaseBranch {
+		p.BaseTip = baseTips[mr.TargetBranch]
+	}
+
+	return p, nil
+}
+
+// listStatuses returns the latest commit status (including pipeline jobs) for each name,
+// with the states translated to the ones used by Github.
+func (m *GitlabClient) listStatuses(sha string) ([]Status, error) {
+	var statuses []Status
+	var page []struct {
+		Name       string     `json:"name"`
+		Status     string     `json:"status"`
+		CreatedAt  time.Time  `json:"created_at"`
+		StartedAt  *time.Time `json:"started_at"`
+		FinishedAt *time.Time `json:"finished_at"`
+	}
+	err := m.api.list(m.projectPath("repository", "commits", sha, "statuses"), nil, &page, func() bool {
+		for _, s := range page {
+			status := Status{Context: s.Name, State: s.Status, UpdatedAt: s.CreatedAt}
+			switch s.Status {
+			case "failed":
+				status.State = "failure"
+			case "canceled":
+				status.State = "cancelled"
+			case "created", "waiting_for_resource", "preparing", "pending", "running", "manual", "scheduled":
+				status.State = "pending"
+			}
+			if s.FinishedAt != nil {
+				status.UpdatedAt = *s.FinishedAt
+			} else if s.StartedAt != nil {
+				status.UpdatedAt = *s.StartedAt
+			}
+			statuses = append(statuses, status)
+		}
+		return true
+	})
+	return statuses, err
+}
+
+// ListModifiedFiles in a merge request.
+func (m *GitlabClient) ListModifiedFiles(prNumber int) ([]string, error) {
+	var files []string
+	var page []gitlabDiff
+	err := m.api.list(m.projectPath("merge_requests", strconv.Itoa(prNumber), "diffs"), nil, &page, func() bool {
+		for _, d := range page {
+			files = append(files, d.NewPath)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// ListModifiedFilesBetween two commits, using the compare API.
+func (m *GitlabClient) ListModifiedFilesBetween(base, head string) ([]string, error) {
+	var comparison struct {
+		Diffs []gitlabDiff `json:"diffs"`
+	}
+	query := url.Values{"from": {base}, "to": {head}}
+	if _, err := m.api.do(http.MethodGet, m.projectPath("repository", "compare"), query, nil, &comparison); err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, d := range comparison.Diffs {
+		files = append(files, d.NewPath)
+	}
+	return files, nil
+}
+
+// ListPullRequestCommits returns the commits in a merge request after the given commit, oldest
+// first. All commits are returned if the given commit is not part of the merge request (e.g.
+// after a force push).
+func (m *GitlabClient) ListPullRequestCommits(prNumber int, after string) ([]CommitObject, error) {
+	var response []CommitObject
+	err := m.walkPullRequestCommits(prNumber, func(c CommitObject) bool {
+		if c.OID == after {
+			return false
+		}
+		response = append([]CommitObject{c}, response...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetPullRequestCommitBefore returns the SHA of the tip of a merge request at the given time, or
+// an empty string if there is none. It is the head of the latest merge request version that was
+// created (i.e. pushed) at or before the time.
+func (m *GitlabClient) GetPullRequestCommitBefore(prNumber int, before time.Time) (string, error) {
+	var sha string
+	var page []gitlabMergeRequestVersion
+	err := m.api.list(m.projectPath("merge_requests", strconv.Itoa(prNumber), "versions"), nil, &page, func() bool {
+		for _, v := range page {
+			if !v.CreatedAt.After(before) {
+				sha = v.HeadCommitSHA
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list versions of merge request %d: %s", prNumber, err)
+	}
+	return sha, nil
+}
+
+// walkPullRequestCommits calls fn for the commits in a merge request, from the tip and
+// backwards, until fn returns false.
+func (m *GitlabClient) walkPullRequestCommits(prNumber int, fn func(CommitObject) bool) error {
+	var page []gitlabCommit
+	return m.api.list(m.projectPath("merge_requests", strconv.Itoa(prNumber), "commits"), nil, &page, func() bool {
+		for _, c := range page {
+			if !fn(c.commitObject()) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// PostComment to a merge request.
+func (m *GitlabClient) PostComment(prNumber, comment string) error {
+	body := map[string]string{"body": comment}
+	_, err := m.api.do(http.MethodPost, m.projectPath("merge_requests", prNumber, "notes"), nil, body, nil)
+	return err
+}
+
+// UpdateComment on a merge request, identified by a hidden marker containing the key. The
+// comment is created if the user has not posted a comment with the same key before.
+func (m *GitlabClient) UpdateComment(prNumber, key, comment string) error {
+	marker := commentMarker(key)
+	body := map[string]string{"body": comment + "\n\n" + marker}
+
+	var id int64
+	err := m.walkOwnNotes(prNumber, func(n gitlabNote) {
+		if strings.Contains(n.Body, marker) {
+			id = n.ID
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if id == 0 {
+		_, err = m.api.do(http.MethodPost, m.projectPath("merge_requests", prNumber, "notes"), nil, body, nil)
+		return err
+	}
+	_, err = m.api.do(http.MethodPut, m.projectPath("merge_requests", prNumber, "notes", strconv.FormatInt(id, 10)), nil, body, nil)
+	return err
+}
+
+// DeletePreviousComments posted to a merge request by the user.
+func (m *GitlabClient) DeletePreviousComments(prNumber string) error {
+	var ids []int64
+	err := m.walkOwnNotes(prNumber, func(n gitlabNote) {
+		ids = append(ids, n.ID)
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, err := m.api.do(http.MethodDelete, m.projectPath("merge_requests", prNumber, "notes", strconv.FormatInt(id, 10)), nil, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkOwnNotes calls fn for the (non-system) notes on a merge request that were posted by
+// the authenticated user, oldest first.
+func (m *GitlabClient) walkOwnNotes(prNumber string, fn func(gitlabNote)) error {
+	var user gitlabUser
+	if _, err := m.api.do(http.MethodGet, "user", nil, nil, &user); err != nil {
+		return err
+	}
+
+	var page []gitlabNote
+	query := url.Values{"sort": {"asc"}, "order_by": {"created_at"}}
+	return m.api.list(m.projectPath("merge_requests", prNumber, "notes"), query, &page, func() bool {
+		for _, n := range page {
+			if !n.System && n.Author.Username == user.Username {
+				fn(n)
+			}
+		}
+		return true
+	})
+}
+
+// GetPullRequest returns the merge request with the given commit as its tip.
+func (m *GitlabClient) GetPullRequest(prNumber, commitRef string) (*PullRequest, error) {
+	var mr gitlabMergeRequest
+	if _, err := m.api.do(http.MethodGet, m.projectPath("merge_requests", prNumber), nil, nil, &mr); err != nil {
+		return nil, err
+	}
+
+	var tip *CommitObject
+	err := m.walkPullRequestCommits(mr.IID, func(c CommitObject) bool {
+		if c.OID == commitRef {
+			tip = &c
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tip == nil {
+		return nil, fmt.Errorf("commit with ref '%s' does not exist", commitRef)
+	}
+	return &PullRequest{
+		PullRequestObject: mr.pullRequestObject(),
+		Tip:               *tip,
+	}, nil
+}
+
+// GetChangedFiles in a merge request.
+func (m *GitlabClient) GetChangedFiles(prNumber string, commitRef string) ([]ChangedFileObject, error) {
+	pr, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert pull request number to int: %s", err)
+	}
+	files, err := m.ListModifiedFiles(pr)
+	if err != nil {
+		return nil, err
+	}
+	var cfo []ChangedFileObject
+	for _, f := range files {
+		cfo = append(cfo, ChangedFileObject{Path: f})
+	}
+	return cfo, nil
+}
+
+// UpdateCommitStatus for a given commit, where the status is translated to a Gitlab state.
+func (m *GitlabClient) UpdateCommitStatus(commitRef, baseContext, statusContext, status, targetURL, description string) error {
+	if baseContext == "" {
+		baseContext = "concourse-ci"
+	}
+
+	if statusContext == "" {
+		statusContext = "status"
+	}
+
+	if targetURL == "" {
+		targetURL = buildURL()
+	}
+
+	if description == "" {
+		description = fmt.Sprintf("Concourse CI build %s", status)
+	}
+
+	state := strings.ToLower(status)
+	if state == "failure" || state == "error" {
+		state = "failed"
+	}
+
+	body := map[string]string{
+		"state":       state,
+		"name":        path.Join(baseContext, statusContext),
+		"target_url":  targetURL,
+		"description": description,
+	}
+	_, err := m.api.do(http.MethodPost, m.projectPath("statuses", commitRef), nil, body, nil)
+	return err
+}
+
+// UpdateCheckRun is not supported by Gitlab.
+func (m *GitlabClient) UpdateCheckRun(commitRef string, run CheckRun) error {
+	return errors.New("check runs are not supported by gitlab")
+}
+
+// CreateReview approves a merge request (if the tip is still at the given commit) or posts
+// the body as a note. Gitlab has no equivalent of requesting changes or inline review comments.
+func (m *GitlabClient) CreateReview(prNumber, commitRef string, review Review) error {
+	if len(review.Comments) > 0 {
+		return errors.New("inline review comments are not supported by gitlab")
+	}
+	switch review.Event {
+	case "APPROVE":
+		body := map[string]string{"sha": commitRef}
+		resp, err := m.api.do(http.MethodPost, m.projectPath("merge_requests", prNumber, "approve"), nil, body, nil)
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusConflict {
+				return fmt.Errorf("head of the merge request has moved: %s", err)
+			}
+			return err
+		}
+	case "COMMENT":
+	default:
+		return fmt.Errorf("%s reviews are not supported by gitlab", strings.ToLower(review.Event))
+	}
+	if review.Body == "" {
+		return nil
+	}
+	return m.PostComment(prNumber, review.Body)
+}
+
+// MergePullRequest if the tip of the merge request is still at the given commit, and returns
+// the SHA of the merge (or squashed) commit.
+func (m *GitlabClient) MergePullRequest(prNumber, commitRef string, merge Merge) (string, error) {
+	var mr gitlabMergeRequest
+	if _, err := m.api.do(http.MethodGet, m.projectPath("merge_requests", prNumber), nil, nil, &mr); err != nil {
+		return "", err
+	}
+	if mr.State == "merged" {
+		return "", errors.New("pull request has already been merged")
+	}
+	if mr.State != "opened" {
+		return "", fmt.Errorf("pull request is %s", mr.State)
+	}
+	if mr.SHA != commitRef {
+		return "", fmt.Errorf("head of the pull request has moved from %s to %s", commitRef, mr.SHA)
+	}
+	if mr.HasConflicts {
+		return "", errors.New("pull request is not mergeable (state: conflicting)")
+	}
+
+	body, err := gitlabMergeBody(commitRef, merge)
+	if err != nil {
+		return "", err
+	}
+	body["should_remove_source_branch"] = merge.DeleteBranch
+
+	var result gitlabMergeRequest
+	resp, err := m.api.do(http.MethodPut, m.projectPath("merge_requests", prNumber, "merge"), nil, body, &result)
+	if err != nil {
+		if resp != nil {
+			switch resp.StatusCode {
+			case http.StatusMethodNotAllowed, http.StatusNotAcceptable, http.StatusUnprocessableEntity:
+				return "", fmt.Errorf("pull request is not mergeable: %s", err)
+			case http.StatusConflict:
+				return "", fmt.Errorf("head of the pull request has moved: %s", err)
+			}
+		}
+		return "", err
+	}
+
+	switch {
+	case result.MergeCommitSHA != "":
+		return result.MergeCommitSHA, nil
+	case result.SquashCommitSHA != "":
+		return result.SquashCommitSHA, nil
+	}
+	// Fast-forward merges do not create a commit.
+	return commitRef, nil
+}
+
+// EnableAutoMerge for a merge request, so that it is merged by Gitlab once the pipeline
+// succeeds. Fails if the tip is no longer at the given commit.
+func (m *GitlabClient) EnableAutoMerge(pullRequestID, commitRef string, merge Merge) error {
+	body, err := gitlabMergeBody(commitRef, merge)
+	if err != nil {
+		return err
+	}
+	body["merge_when_pipeline_succeeds"] = true
+
+	resp, err := m.api.do(http.MethodPut, m.projectPath("merge_requests", pullRequestID, "merge"), nil, body, nil)
+	if err != nil && resp != nil && resp.StatusCode == http.StatusConflict {
+		return fmt.Errorf("head of the pull request has moved: %s", err)
+	}
+	return err
+}
+
+// gitlabMergeBody returns the parameters for merging a merge request. The commit title and
+// message are combined, since Gitlab only accepts a message.
+func gitlabMergeBody(commitRef string, merge Merge) (map[string]interface{}, error) {
+	body := map[string]interface{}{"sha": commitRef}
+
+	message := merge.CommitTitle
+	if merge.CommitMessage != "" {
+		if message != "" {
+			message += "\n\n"
+		}
+		message += merge.CommitMessage
+	}
+
+	switch merge.Method {
+	case "", "merge":
+		if message != "" {
+			body["merge_commit_message"] = message
+		}
+	case "squash":
+		body["squash"] = true
+		if message != "" {
+			body["squash_commit_message"] = message
+		}
+	default:
+		return nil, fmt.Errorf("%s merges are not supported by gitlab", merge.Method)
+	}
+	return body, nil
+}
+
+// EnqueuePullRequest is not supported by Gitlab (merge trains are configured for the project).
+func (m *GitlabClient) EnqueuePullRequest(pullRequestID, commitRef string) error {
+	return errors.New("merge queues are not supported by gitlab")
+}
+
+// AddLabels to a merge request. Gitlab creates missing labels, so unless createMissing is true
+// the labels are checked against the labels of the project first.
+func (m *GitlabClient) AddLabels(prNumber string, labels []string, createMissing bool) error {
+	if !createMissing {
+		existing := make(map[string]bool)
+		var page []struct {
+			Name string `json:"name"`
+		}
+		err := m.api.list(m.projectPath("labels"), nil, &page, func() bool {
+			for _, l := range page {
+				existing[strings.ToLower(l.Name)] = true
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		for _, l := range labels {
+			if !existing[strings.ToLower(l)] {
+				return fmt.Errorf("label '%s' does not exist", l)
+			}
+		}
+	}
+
+	body := map[string]string{"add_labels": strings.Join(labels, ",")}
+	_, err := m.api.do(http.MethodPut, m.projectPath("merge_requests", prNumber), nil, body, nil)
+	return err
+}
+
+// RemoveLabels from a merge request. Labels that are not set on the merge request are ignored.
+func (m *GitlabClient) RemoveLabels(prNumber string, labels []string) error {
+	body := map[string]string{"remove_labels": strings.Join(labels, ",")}
+	_, err := m.api.do(http.MethodPut, m.projectPath("merge_requests", prNumber), nil, body, nil)
+	return err
+}
+
+// GetComment (note) on a merge request by its ID.
+func (m *GitlabClient) GetComment(prNumber, commentID string) (*CommentObject, error) {
+	var note gitlabNote
+	if _, err := m.api.do(http.MethodGet, m.projectPath("merge_requests", prNumber, "notes", commentID), nil, nil, &note); err != nil {
+		return nil, err
+	}
+	comment := note.commentObject()
+	return &comment, nil
+}
+
+// ListTeamMembers returns the usernames of all members of a (sub)group.
+func (m *GitlabClient) ListTeamMembers(org, team string) ([]string, error) {
+	var members []string
+	var page []gitlabUser
+	err := m.api.list("groups/"+url.PathEscape(org+"/"+team)+"/members/all", nil, &page, func() bool {
+		for _, u := range page {
+			members = append(members, u.Username)
+		}
+		return true
+	})
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("team '%s/%s' does not exist", org, team)
+		}
+		return nil, err
+	}
+	return members, nil
+}
+
+// projectPath returns the API path of the project followed by the (escaped) path segments.
+func (m *GitlabClient) projectPath(segments ...string) string {
+	p := "projects/" + url.PathEscape(m.Project)
+	for _, s := range segments {
+		p += "/" + url.PathEscape(s)
+	}
+	return p
+}
+
+// pullRequestObject translates the merge request, where the repository URL is that of the
+// target project.
+func (mr *gitlabMergeRequest) pullRequestObject() PullRequestObject {
+	p := PullRequestObject{
+		ID:                strconv.Itoa(mr.IID),
+		Number:            mr.IID,
+		Title:             mr.Title,
+		URL:               mr.WebURL,
+		BaseRefName:       mr.TargetBranch,
+		HeadRefName:       mr.SourceBranch,
+		IsCrossRepository: mr.SourceProjectID != mr.TargetProjectID,
+		IsDraft:           mr.Draft || mr.WorkInProgress,
+		Mergeable:         MergeableStateMergeable,
+		UpdatedAt:         mr.UpdatedAt,
+	}
+	if i := strings.Index(mr.WebURL, "/-/merge_requests/"); i >= 0 {
+		p.Repository.URL = mr.WebURL[:i]
+	}
+	switch {
+	case mr.HasConflicts:
+		p.Mergeable = MergeableStateConflicting
+	case mr.MergeStatus == "unchecked" || mr.MergeStatus == "checking":
+		p.Mergeable = MergeableStateUnknown
+	}
+	p.Author.Login = mr.Author.Username
+	return p
+}
+
+func (c *gitlabCommit) commitObject() CommitObject {
+	return CommitObject{
+		ID:            c.ID,
+		OID:           c.ID,
+		CommittedDate: githubv4.DateTime{Time: c.CommittedDate},
+		Message:       c.Message,
+	}
+}
+
+func (n *gitlabNote) commentObject() CommentObject {
+	c := CommentObject{
+		DatabaseId:        n.ID,
+		Body:              n.Body,
+		CreatedAt:         n.CreatedAt,
+		AuthorAssociation: CommentAuthorAssociationNone,
+	}
+	c.Author.Login = n.Author.Username
+	return c
+}
@@ -0,0 +1,609 @@
+package resource_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+// gitlabProject is the (escaped) API path of the test project.
+const gitlabProject = "/api/v4/projects/itsdalmo%2Fsub%2Ftest-repository"
+
+func newGitlabTestClient(t *testing.T, server *httptest.Server, source resource.Source) *resource.GitlabClient {
+	source.Provider = "gitlab"
+	source.Repository = "itsdalmo/sub/test-repository"
+	source.AccessToken = "oauthtoken"
+	source.Endpoint = server.URL + "/api/v4"
+	require.NoError(t, source.Validate())
+
+	client, err := resource.NewGitlabClient(&source, nil)
+	require.NoError(t, err)
+	return client
+}
+
+var gitlabMergeRequests = `[
+	{
+		"iid": 2,
+		"title": "second",
+		"web_url": "https://gitlab.example.com/itsdalmo/sub/test-repository/-/merge_requests/2",
+		"source_branch": "feature",
+		"target_branch": "master",
+		"source_project_id": 2,
+		"target_project_id": 1,
+		"draft": true,
+		"has_conflicts": true,
+		"sha": "oid2",
+		"labels": ["bug"],
+		"author": {"username": "someone"},
+		"updated_at": "2020-01-02T00:00:00Z"
+	}
+]`
+
+func TestGitlabClientListOpenPullRequests(t *testing.T) {
+	tests := []struct {
+		description string
+		source      resource.Source
+		responses   map[string]string
+		expected    resource.PullRequest
+	}{
+		{
+			description: "lists merge requests",
+			responses:   map[string]string{},
+			expected: resource.PullRequest{
+				Labels: []resource.LabelObject{{Name: "bug"}},
+			},
+		},
+		{
+			description: "requests the details used by the source configuration",
+			source: resource.Source{
+				RequiredReviewApprovals: 1,
+				TriggerPhrase:           "test this",
+				Labels:                  []string{"bug"},
+				RequiredStatuses:        []resource.RequiredStatus{{Context: "build"}},
+				TrackBaseBranch:         true,
+			},
+			responses: map[string]string{
+				"GET " + gitlabProject + "/members/all":                            `[{"username":"member","access_level":30},{"username":"guest","access_level":10}]`,
+				"GET " + gitlabProject + "/merge_requests/2/approvals":             `{"approved_by":[{"user":{"username":"member"}}]}`,
+				"GET " + gitlabProject + "/merge_requests/2/notes":                 `[{"id":12,"body":"test this","created_at":"2020-01-04T00:00:00Z","author":{"username":"guest"}},{"id":11,"body":"added 1 commit","system":true},{"id":10,"body":"test this","created_at":"2020-01-03T00:00:00Z","author":{"username":"member"}}]`,
+				"GET " + gitlabProject + "/merge_requests/2/resource_label_events": `[{"action":"add","created_at":"2020-01-05T00:00:00Z","label":{"name":"bug"}}]`,
+				"GET " + gitlabProject + "/repository/commits/oid2/statuses":       `[{"name":"build","status":"failed","created_at":"2020-01-06T00:00:00Z","finished_at":"2020-01-07T00:00:00Z"},{"name":"lint","status":"running","created_at":"2020-01-06T00:00:00Z"}]`,
+				"GET " + gitlabProject + "/repository/branches/master":             `{"commit":{"id":"base","committed_date":"2020-01-08T00:00:00Z"}}`,
+			},
+			expected: resource.PullRequest{
+				ApprovedReviewCount: 1,
+				Labels:              []resource.LabelObject{{Name: "bug"}},
+				Comments: []resource.CommentObject{
+					gitlabTestComment(10, "member", resource.CommentAuthorAssociationMember, time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)),
+					gitlabTestComment(12, "guest", resource.CommentAuthorAssociationNone, time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)),
+				},
+				LabelEvents: []resource.LabelEvent{{Name: "bug", Added: true, CreatedAt: time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC)}},
+				Statuses: []resource.Status{
+					{Context: "build", State: "failure", UpdatedAt: time.Date(2020, 1, 7, 0, 0, 0, 0, time.UTC)},
+					{Context: "lint", State: "pending", UpdatedAt: time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)},
+				},
+				BaseTip: resource.CommitObject{ID: "base", OID: "base", CommittedDate: githubv4.DateTime{Time: time.Date(2020, 1, 8, 0, 0, 0, 0, time.UTC)}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			responses := map[string]string{
+				"GET " + gitlabProject + "/merge_requests":          gitlabMergeRequests,
+				"GET " + gitlabProject + "/repository/commits/oid2": `{"id":"oid2","message":"commit","committed_date":"2020-01-01T00:00:00Z"}`,
+			}
+			for k, v := range tc.responses {
+				responses[k] = v
+			}
+			g, server := newAPIStandIn(t, responses)
+			defer server.Close()
+
+			pulls, err := newGitlabTestClient(t, server, tc.source).ListOpenPullRequests()
+			require.NoError(t, err)
+			require.Len(t, pulls, 1)
+
+			// Only the endpoints used by the source configuration are requested.
+			assert.Len(t, g.requests, len(responses))
+
+			p := pulls[0]
+			assert.Equal(t, "2", p.ID)
+			assert.Equal(t, 2, p.Number)
+			assert.Equal(t, "second", p.Title)
+			assert.Equal(t, "https://gitlab.example.com/itsdalmo/sub/test-repository", p.Repository.URL)
+			assert.Equal(t, "master", p.BaseRefName)
+			assert.Equal(t, "feature", p.HeadRefName)
+			assert.True(t, p.IsCrossRepository)
+			assert.True(t, p.IsDraft)
+			assert.Equal(t, resource.MergeableStateConflicting, p.Mergeable)
+			assert.Equal(t, "someone", p.Author.Login)
+			assert.Equal(t, "oid2", p.Tip.OID)
+			assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), p.Tip.CommittedDate.Time)
+			assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), p.UpdatedAt)
+
+			assert.Equal(t, tc.expected.ApprovedReviewCount, p.ApprovedReviewCount)
+			assert.Equal(t, tc.expected.Labels, p.Labels)
+			assert.Equal(t, tc.expected.Comments, p.Comments)
+			assert.Equal(t, tc.expected.LabelEvents, p.LabelEvents)
+			assert.Equal(t, tc.expected.Statuses, p.Statuses)
+			assert.Equal(t, tc.expected.BaseTip, p.BaseTip)
+		})
+	}
+}
+
+func TestGitlabClientListOpenPullRequestsFilters(t *testing.T) {
+	g, server := newAPIStandIn(t, map[string]string{
+		"GET " + gitlabProject + "/merge_requests": `[]`,
+	})
+	defer server.Close()
+
+	source := resource.Source{BaseBranch: "develop", IgnoreDrafts: true}
+	pulls, err := newGitlabTestClient(t, server, source).ListOpenPullRequests()
+	if assert.NoError(t, err) {
+		assert.Len(t, pulls, 0)
+	}
+	query := g.queries["GET "+gitlabProject+"/merge_requests"]
+	assert.Equal(t, "opened", query.Get("state"))
+	assert.Equal(t, "develop", query.Get("target_branch"))
+	assert.Equal(t, "no", query.Get("wip"))
+}
+
+func TestGitlabClientListModifiedFiles(t *testing.T) {
+	g, server := newAPIStandIn(t, map[string]string{
+		"GET " + gitlabProject + "/merge_requests/1/diffs":        `[{"new_path":"README.md"}]`,
+		"GET " + gitlabProject + "/merge_requests/1/diffs?page=2": `[{"new_path":"terraform/main.tf"}]`,
+		"GET " + gitlabProject + "/repository/compare":            `{"diffs":[{"new_path":"main.go"}]}`,
+	})
+	defer server.Close()
+	client := newGitlabTestClient(t, server, resource.Source{})
+
+	files, err := client.ListModifiedFiles(1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"README.md", "terraform/main.tf"}, files)
+	}
+
+	files, err = client.ListModifiedFilesBetween("base", "head")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"main.go"}, files)
+	}
+	assert.Len(t, g.requests, 3)
+}
+
+func TestGitlabClientGetPullRequest(t *testing.T) {
+	_, server := newAPIStandIn(t, map[string]string{
+		"GET " + gitlabProject + "/merge_requests/2":                `{"iid":2,"title":"second","target_branch":"master","web_url":"https://gitlab.example.com/itsdalmo/sub/test-repository/-/merge_requests/2"}`,
+		"GET " + gitlabProject + "/merge_requests/2/commits":        `[{"id":"oid3","message":"third"},{"id":"oid2","message":"second"}]`,
+		"GET " + gitlabProject + "/merge_requests/2/commits?page=2": `[{"id":"oid1","message":"first","committed_date":"2020-01-01T00:00:00Z"}]`,
+	})
+	defer server.Close()
+	client := newGitlabTestClient(t, server, resource.Source{})
+
+	pull, err := client.GetPullRequest("2", "oid1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, pull.Number)
+		assert.Equal(t, "master", pull.BaseRefName)
+		assert.Equal(t, "https://gitlab.example.com/itsdalmo/sub/test-repository", pull.Repository.URL)
+		assert.Equal(t, "oid1", pull.Tip.OID)
+		assert.Equal(t, "first", pull.Tip.Message)
+	}
+
+	_, err = client.GetPullRequest("2", "missing")
+	assert.EqualError(t, err, "commit with ref 'missing' does not exist")
+
+	commits, err := client.ListPullRequestCommits(2, "oid1")
+	if assert.NoError(t, err) && assert.Len(t, commits, 2) {
+		assert.Equal(t, "oid2", commits[0].OID)
+		assert.Equal(t, "oid3", commits[1].OID)
+	}
+}
+
+func TestGitlabClientGetPullRequestCommitBefore(t *testing.T) {
+	_, server := newAPIStandIn(t, map[string]string{
+		"GET " + gitlabProject + "/merge_requests/2/versions": `[
+			{"head_commit_sha":"oid3","created_at":"2020-01-03T00:00:00Z"},
+			{"head_commit_sha":"oid2","created_at":"2020-01-02T00:00:00Z"}
+		]`,
+		"GET " + gitlabProject + "/merge_requests/2/versions?page=2": `[{"head_commit_sha":"oid1","created_at":"2020-01-01T00:00:00Z"}]`,
+	})
+	defer server.Close()
+	client := newGitlabTestClient(t, server, resource.Source{})
+
+	tests := []struct {
+		before   time.Time
+		expected string
+	}{
+		{before: time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC), expected: "oid3"},
+		{before: time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC), expected: "oid2"},
+		{before: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), expected: "oid1"},
+		{before: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), expected: ""},
+	}
+	for _, tc := range tests {
+		sha, err := client.GetPullRequestCommitBefore(2, tc.before)
+		if assert.NoError(t, err) {
+			assert.Equal(t, tc.expected, sha)
+		}
+	}
+}
+
+func TestGitlabClientUpdateCommitStatus(t *testing.T) {
+	tests := []struct {
+		status   string
+		expected string
+	}{
+		{status: "success", expected: `{"description":"Concourse CI build success","name":"concourse-ci/status","state":"success","target_url":"https://ci.example.com"}`},
+		{status: "pending", expected: `{"description":"Concourse CI build pending","name":"concourse-ci/status","state":"pending","target_url":"https://ci.example.com"}`},
+		{status: "failure", expected: `{"description":"Concourse CI build failure","name":"concourse-ci/status","state":"failed","target_url":"https://ci.example.com"}`},
+		{status: "error", expected: `{"description":"Concourse CI build error","name":"concourse-ci/status","state":"failed","target_url":"https://ci.example.com"}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.status, func(t *testing.T) {
+			key := "POST " + gitlabProject + "/statuses/oid"
+			g, server := newAPIStandIn(t, map[string]string{key: `{}`})
+			defer server.Close()
+
+			err := newGitlabTestClient(t, server, resource.Source{}).UpdateCommitStatus("oid", "", "", tc.status, "https://ci.example.com", "")
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, g.bodies[key])
+			}
+		})
+	}
+}
+
+func TestGitlabClientUpdateComment(t *testing.T) {
+	tests := []struct {
+		description     string
+		notes           string
+		expectedRequest string
+	}{
+		{
+			description:     "creates a note when none has the key",
+			notes:           `[{"id":1,"body":"hello","author":{"username":"bot"}}]`,
+			expectedRequest: "POST " + gitlabProject + "/merge_requests/1/notes",
+		},
+		{
+			description: "updates the note with the same key",
+			notes: `[
+				{"id":1,"body":"plan\n\n<!-- github-pr-resource: plan -->","author":{"username":"bot"}},
+				{"id":2,"body":"lint\n\n<!-- github-pr-resource: lint -->","author":{"username":"bot"}}
+			]`,
+			expectedRequest: "PUT " + gitlabProject + "/merge_requests/1/notes/1",
+		},
+		{
+			description:     "ignores notes by other users",
+			notes:           `[{"id":1,"body":"> <!-- github-pr-resource: plan -->","author":{"username":"someone"}}]`,
+			expectedRequest: "POST " + gitlabProject + "/merge_requests/1/notes",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			g, server := newAPIStandIn(t, map[string]string{
+				"GET /api/v4/user": `{"username":"bot"}`,
+				"GET " + gitlabProject + "/merge_requests/1/notes":   tc.notes,
+				"POST " + gitlabProject + "/merge_requests/1/notes":  `{}`,
+				"PUT " + gitlabProject + "/merge_requests/1/notes/1": `{}`,
+			})
+			defer server.Close()
+
+			err := newGitlabTestClient(t, server, resource.Source{}).UpdateComment("1", "plan", "new plan")
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expectedRequest, g.requests[len(g.requests)-1])
+				assert.Equal(t, `{"body":"new plan\n\n<!-- github-pr-resource: plan -->"}`, g.bodies[tc.expectedRequest])
+			}
+		})
+	}
+}
+
+func TestGitlabClientCreateReview(t *testing.T) {
+	tests := []struct {
+		description      string
+		review           resource.Review
+		approve          string
+		expectedRequests []string
+		expectedErr      string
+	}{
+		{
+			description:      "approves the merge request",
+			review:           resource.Review{Event: "APPROVE"},
+			approve:          `{}`,
+			expectedRequests: []string{"POST " + gitlabProject + "/merge_requests/1/approve"},
+		},
+		{
+			description:      "posts the body of an approval as a note",
+			review:           resource.Review{Event: "APPROVE", Body: "lgtm"},
+			approve:          `{}`,
+			expectedRequests: []string{"POST " + gitlabProject + "/merge_requests/1/approve", "POST " + gitlabProject + "/merge_requests/1/notes"},
+		},
+		{
+			description:      "posts comments as notes",
+			review:           resource.Review{Event: "COMMENT", Body: "hmm"},
+			expectedRequests: []string{"POST " + gitlabProject + "/merge_requests/1/notes"},
+		},
+		{
+			description:      "fails if the head has moved",
+			review:           resource.Review{Event: "APPROVE"},
+			approve:          `409 SHA does not match HEAD of source branch`,
+			expectedRequests: []string{"POST " + gitlabProject + "/merge_requests/1/approve"},
+			expectedErr:      "head of the merge request has moved",
+		},
+		{
+			description: "does not support requesting changes",
+			review:      resource.Review{Event: "REQUEST_CHANGES", Body: "no"},
+			expectedErr: "request_changes reviews are not supported by gitlab",
+		},
+		{
+			description: "does not support inline comments",
+			review:      resource.Review{Event: "COMMENT", Body: "hmm", Comments: []resource.ReviewComment{{Path: "main.go", Line: 1, Body: "here"}}},
+			expectedErr: "inline review comments are not supported by gitlab",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			g, server := newAPIStandIn(t, map[string]string{
+				"POST " + gitlabProject + "/merge_requests/1/approve": tc.approve,
+				"POST " + gitlabProject + "/merge_requests/1/notes":   `{}`,
+			})
+			defer server.Close()
+
+			err := newGitlabTestClient(t, server, resource.Source{}).CreateReview("1", "oid", tc.review)
+			if tc.expectedErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.expectedErr)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedRequests, g.requests)
+			if len(tc.expectedRequests) > 0 && tc.review.Event == "APPROVE" {
+				assert.Equal(t, `{"sha":"oid"}`, g.bodies[tc.expectedRequests[0]])
+			}
+		})
+	}
+}
+
+func TestGitlabClientMergePullRequest(t *testing.T) {
+	tests := []struct {
+		description  string
+		mergeRequest string
+		merge        resource.Merge
+		result       string
+		expectedBody string
+		expectedSHA  string
+		expectedErr  string
+	}{
+		{
+			description:  "merges the merge request",
+			mergeRequest: `{"iid":1,"state":"opened","sha":"oid"}`,
+			merge:        resource.Merge{Method: "merge", CommitTitle: "title", CommitMessage: "message", DeleteBranch: true},
+			result:       `{"merge_commit_sha":"merged"}`,
+			expectedBody: `{"merge_commit_message":"title\n\nmessage","sha":"oid","should_remove_source_branch":true}`,
+			expectedSHA:  "merged",
+		},
+		{
+			description:  "squashes the merge request",
+			mergeRequest: `{"iid":1,"state":"opened","sha":"oid"}`,
+			merge:        resource.Merge{Method: "squash", CommitTitle: "title"},
+			result:       `{"squash_commit_sha":"squashed"}`,
+			expectedBody: `{"sha":"oid","should_remove_source_branch":false,"squash":true,"squash_commit_message":"title"}`,
+			expectedSHA:  "squashed",
+		},
+		{
+			description:  "returns the tip for fast-forward merges",
+			mergeRequest: `{"iid":1,"state":"opened","sha":"oid"}`,
+			merge:        resource.Merge{Method: "merge"},
+			result:       `{}`,
+			expectedBody: `{"sha":"oid","should_remove_source_branch":false}`,
+			expectedSHA:  "oid",
+		},
+		{
+			description:  "fails if the head has moved",
+			mergeRequest: `{"iid":1,"state":"opened","sha":"new"}`,
+			merge:        resource.Merge{Method: "merge"},
+			expectedErr:  "head of the pull request has moved from oid to new",
+		},
+		{
+			description:  "fails if the merge request has been merged",
+			mergeRequest: `{"iid":1,"state":"merged","sha":"oid"}`,
+			merge:        resource.Merge{Method: "merge"},
+			expectedErr:  "pull request has already been merged",
+		},
+		{
+			description:  "fails if the merge request has conflicts",
+			mergeRequest: `{"iid":1,"state":"opened","sha":"oid","has_conflicts":true}`,
+			merge:        resource.Merge{Method: "merge"},
+			expectedErr:  "pull request is not mergeable (state: conflicting)",
+		},
+		{
+			description:  "fails if the merge is not allowed",
+			mergeRequest: `{"iid":1,"state":"opened","sha":"oid"}`,
+			merge:        resource.Merge{Method: "merge"},
+			result:       `405 Method Not Allowed`,
+			expectedBody: `{"sha":"oid","should_remove_source_branch":false}`,
+			expectedErr:  "pull request is not mergeable",
+		},
+		{
+			description:  "does not support rebase merges",
+			mergeRequest: `{"iid":1,"state":"opened","sha":"oid"}`,
+			merge:        resource.Merge{Method: "rebase"},
+			expectedErr:  "rebase merges are not supported by gitlab",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			key := "PUT " + gitlabProject + "/merge_requests/1/merge"
+			g, server := newAPIStandIn(t, map[string]string{
+				"GET " + gitlabProject + "/merge_requests/1": tc.mergeRequest,
+				key: tc.result,
+			})
+			defer server.Close()
+
+			sha, err := newGitlabTestClient(t, server, resource.Source{}).MergePullRequest("1", "oid", tc.merge)
+			if tc.expectedErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.expectedErr)
+				}
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tc.expectedSHA, sha)
+			}
+			assert.Equal(t, tc.expectedBody, g.bodies[key])
+		})
+	}
+}
+
+func TestGitlabClientLabels(t *testing.T) {
+	tests := []struct {
+		description   string
+		createMissing bool
+		labels        []string
+		expectedBody  string
+		expectedErr   string
+	}{
+		{
+			description:  "adds existing labels",
+			labels:       []string{"Bug", "review"},
+			expectedBody: `{"add_labels":"Bug,review"}`,
+		},
+		{
+			description: "fails for missing labels",
+			labels:      []string{"bug", "missing"},
+			expectedErr: "label 'missing' does not exist",
+		},
+		{
+			description:   "lets gitlab create missing labels",
+			createMissing: true,
+			labels:        []string{"bug", "missing"},
+			expectedBody:  `{"add_labels":"bug,missing"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			key := "PUT " + gitlabProject + "/merge_requests/1"
+			g, server := newAPIStandIn(t, map[string]string{
+				"GET " + gitlabProject + "/labels":        `[{"name":"bug"}]`,
+				"GET " + gitlabProject + "/labels?page=2": `[{"name":"review"}]`,
+				key: `{}`,
+			})
+			defer server.Close()
+
+			err := newGitlabTestClient(t, server, resource.Source{}).AddLabels("1", tc.labels, tc.createMissing)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedBody, g.bodies[key])
+		})
+	}
+}
+
+func TestGitlabCheck(t *testing.T) {
+	_, server := newAPIStandIn(t, map[string]string{
+		"GET " + gitlabProject + "/merge_requests": `[
+			{"iid":1,"title":"first","sha":"oid1","target_branch":"master","labels":["bug"]},
+			{"iid":2,"title":"second [skip ci]","sha":"oid2","target_branch":"master"},
+			{"iid":3,"title":"third","sha":"oid3","target_branch":"master"}
+		]`,
+		"GET " + gitlabProject + "/repository/commits/oid1":                `{"id":"oid1","committed_date":"2020-01-03T00:00:00Z"}`,
+		"GET " + gitlabProject + "/repository/commits/oid2":                `{"id":"oid2","committed_date":"2020-01-02T00:00:00Z"}`,
+		"GET " + gitlabProject + "/repository/commits/oid3":                `{"id":"oid3","committed_date":"2020-01-01T00:00:00Z"}`,
+		"GET " + gitlabProject + "/merge_requests/1/diffs":                 `[{"new_path":"README.md"}]`,
+		"GET " + gitlabProject + "/merge_requests/3/diffs":                 `[{"new_path":"terraform/main.tf"}]`,
+		"GET " + gitlabProject + "/merge_requests/1/resource_label_events": `[]`,
+		"GET " + gitlabProject + "/merge_requests/2/resource_label_events": `[]`,
+		"GET " + gitlabProject + "/merge_requests/3/resource_label_events": `[]`,
+	})
+	defer server.Close()
+
+	source := resource.Source{IgnoreLabels: []string{"bug"}, Paths: []string{"terraform/*.tf"}}
+	client := newGitlabTestClient(t, server, source)
+
+	request := resource.CheckRequest{Source: source}
+	response, err := resource.Check(request, client)
+	if assert.NoError(t, err) {
+		assert.Equal(t, resource.CheckResponse{
+			{PR: "3", Commit: "oid3", CommittedDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}, response)
+	}
+}
+
+func TestSourceValidateProvider(t *testing.T) {
+	tests := []struct {
+		description string
+		source      resource.Source
+		expected    string
+	}{
+		{
+			description: "github is the default",
+			source:      resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+		},
+		{
+			description: "gitlab is valid",
+			source:      resource.Source{Provider: "gitlab", Repository: "itsdalmo/sub/test-repository", AccessToken: "oauthtoken", Endpoint: "https://gitlab.example.com/api/v4"},
+		},
+		{
+			description: "forgejo is valid",
+			source:      resource.Source{Provider: "Forgejo", Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", Endpoint: "https://forgejo.example.com/api/v1"},
+		},
+		{
+			description: "requires an endpoint for gitea",
+			source:      resource.Source{Provider: "gitea", Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+			expected:    "endpoint must be set for gitea",
+		},
+		{
+			description: "requires a known provider",
+			source:      resource.Source{Provider: "bitbucket", Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+			expected:    "unknown provider: bitbucket",
+		},
+		{
+			description: "does not allow endpoint for github",
+			source:      resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", Endpoint: "https://github.example.com"},
+			expected:    "endpoint is not supported for github (use v3_endpoint and v4_endpoint)",
+		},
+		{
+			description: "does not allow github endpoints for gitlab",
+			source:      resource.Source{Provider: "gitlab", Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", V3Endpoint: "https://gitlab.example.com", V4Endpoint: "https://gitlab.example.com"},
+			expected:    "v3_endpoint and v4_endpoint are not supported for gitlab (use endpoint)",
+		},
+		{
+			description: "does not allow github apps for gitlab",
+			source:      resource.Source{Provider: "gitlab", Repository: "itsdalmo/test-repository", GithubAppID: 1, GithubAppInstallationID: 2, GithubAppPrivateKey: "key"},
+			expected:    "github_app_id is not supported for gitlab",
+		},
+		{
+			description: "does not allow merge queues for gitlab",
+			source:      resource.Source{Provider: "gitlab", Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken", MergeQueue: true},
+			expected:    "merge_queue is not supported for gitlab",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			err := tc.source.Validate()
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
+		})
+	}
+}
+
+func gitlabTestComment(id int64, author string, association resource.CommentAuthorAssociation, createdAt time.Time) resource.CommentObject {
+	c := resource.CommentObject{
+		DatabaseId:        id,
+		Body:              "test this",
+		CreatedAt:         createdAt,
+		AuthorAssociation: association,
+	}
+	c.Author.Login = author
+	return c
+}
@@ -2,6 +2,7 @@ package resource
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -10,7 +11,7 @@ import (
 )
 
 // Get (business logic)
-func Get(request GetRequest, github Github, git Git, outputDir string) (*GetResponse, error) {
+func Get(request GetRequest, github Provider, git Git, outputDir string) (*GetResponse, error) {
 	if request.Params.SkipDownload {
 		return &GetResponse{Version: request.Version}, nil
 	}
@@ -20,11 +21,16 @@ func Get(request GetRequest, github Github, git Git, outputDir string) (*GetResp
 		return nil, fmt.Errorf("failed to retrieve pull request: %s", err)
 	}
 
-	// Initialize and pull the base for the PR
+	// Initialize and pull the base for the PR, which is the commit of the base branch in the
+	// version (when tracked) so the version is built against the same base every time.
 	if err := git.Init(pull.BaseRefName); err != nil {
 		return nil, err
 	}
-	if err := git.Pull(pull.Repository.URL, pull.BaseRefName, request.Params.GitDepth); err != nil {
+	base := pull.BaseRefName
+	if request.Version.BaseCommit != "" {
+		base = request.Version.BaseCommit
+	}
+	if err := git.Pull(pull.Repository.URL, base, request.Params.GitDepth); err != nil {
 		return nil, err
 	}
 
@@ -39,16 +45,48 @@ func Get(request GetRequest, github Github, git Git, outputDir string) (*GetResp
 		return nil, err
 	}
 
-	switch tool := request.Params.IntegrationTool; tool {
-	case "rebase":
-		if err := git.Rebase(pull.BaseRefName, pull.Tip.OID); err != nil {
+	// Notify the pull request about merge conflicts if specified, before failing.
+	onConflict := func(err error) error {
+		var conflict *MergeConflictError
+		if !errors.As(err, &conflict) {
+			return err
+		}
+		if request.Params.ConflictComment {
+			comment := fmt.Sprintf("This pull request conflicts with `%s` and needs a rebase. Conflicting files:\n", pull.BaseRefName)
+			for _, f := range conflict.Files {
+				comment += fmt.Sprintf("\n- `%s`", f)
+			}
+			if err := github.UpdateComment(request.Version.PR, "merge-conflict", comment); err != nil {
+				return fmt.Errorf("%s (failed to post comment: %s)", conflict, err)
+			}
+		}
+		if request.Params.ConflictStatus {
+			description := fmt.Sprintf("Conflicts with %s, needs a rebase", pull.BaseRefName)
+			if err := github.UpdateCommitStatus(request.Version.Commit, "", "merge-conflict", "failure", "", description); err != nil {
+				return fmt.Errorf("%s (failed to set status: %s)", conflict, err)
+			}
+		}
+		return conflict
+	}
+
+	// Merge groups already contain the base and the pull request, so the commit is checked out as is.
+	switch tool := request.Params.IntegrationTool; {
+	case request.Version.MergeGroup != "":
+		if err := git.FetchRef(pull.Repository.URL, request.Version.MergeGroup, request.Params.GitDepth); err != nil {
 			return nil, err
 		}
-	case "merge", "":
-		if err := git.Merge(pull.Tip.OID); err != nil {
+		if err := git.Checkout("merge-group", request.Version.MergeGroup); err != nil {
 			return nil, err
 		}
-	case "checkout":
+	case tool == "rebase":
+		if err := git.Rebase(pull.BaseRefName, pull.Tip.OID); err != nil {
+			return nil, onConflict(err)
+		}
+	case tool == "merge", tool == "":
+		if err := git.Merge(pull.Tip.OID); err != nil {
+			return nil, onConflict(err)
+		}
+	case tool == "checkout":
 		if err := git.Checkout(pull.HeadRefName, pull.Tip.OID); err != nil {
 			return nil, err
 		}
@@ -72,6 +110,20 @@ func Get(request GetRequest, github Github, git Git, outputDir string) (*GetResp
 	metadata.Add("base_sha", baseSHA)
 	metadata.Add("message", pull.Tip.Message)
 	metadata.Add("author", pull.Tip.Author.User.Login)
+	metadata.Add("draft", strconv.FormatBool(pull.IsDraft))
+	if request.Version.MergeGroup != "" {
+		metadata.Add("merge_group_sha", request.Version.MergeGroup)
+	}
+
+	// Add the comment that triggered the version, if any.
+	if request.Version.Comment != "" {
+		comment, err := github.GetComment(request.Version.PR, request.Version.Comment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve comment: %s", err)
+		}
+		metadata.Add("trigger_comment", comment.Body)
+		metadata.Add("trigger_comment_author", comment.Author.Login)
+	}
 
 	// Write version and metadata for reuse in PUT
 	path := filepath.Join(outputDir, ".git", "resource")
@@ -132,6 +184,8 @@ type GetParameters struct {
 	IntegrationTool  string `json:"integration_tool"`
 	GitDepth         int    `json:"git_depth"`
 	ListChangedFiles bool   `json:"list_changed_files"`
+	ConflictComment  bool   `json:"conflict_comment"`
+	ConflictStatus   bool   `json:"conflict_status"`
 }
 
 // GetRequest ...
@@ -27,6 +27,7 @@ func TestGet(t *testing.T) {
 		metadataString string
 		files          []resource.ChangedFileObject
 		filesString    string
+		comment        *resource.CommentObject
 	}{
 		{
 			description: "get works",
@@ -42,7 +43,7 @@ func TestGet(t *testing.T) {
 			parameters:     resource.GetParameters{},
 			pullRequest:    createTestPR(1, "master", false, false, 0, nil),
 			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z"}`,
-			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"}]`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"draft","value":"false"}]`,
 		},
 		{
 			description: "get supports unlocking with git crypt",
@@ -59,7 +60,7 @@ func TestGet(t *testing.T) {
 			parameters:     resource.GetParameters{},
 			pullRequest:    createTestPR(1, "master", false, false, 0, nil),
 			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z"}`,
-			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"}]`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"draft","value":"false"}]`,
 		},
 		{
 			description: "get supports rebasing",
@@ -77,7 +78,7 @@ func TestGet(t *testing.T) {
 			},
 			pullRequest:    createTestPR(1, "master", false, false, 0, nil),
 			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z"}`,
-			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"}]`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"draft","value":"false"}]`,
 		},
 		{
 			description: "get supports checkout",
@@ -95,7 +96,7 @@ func TestGet(t *testing.T) {
 			},
 			pullRequest:    createTestPR(1, "master", false, false, 0, nil),
 			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z"}`,
-			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"}]`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"draft","value":"false"}]`,
 		},
 		{
 			description: "get supports git_depth",
@@ -113,7 +114,7 @@ func TestGet(t *testing.T) {
 			},
 			pullRequest:    createTestPR(1, "master", false, false, 0, nil),
 			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z"}`,
-			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"}]`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"draft","value":"false"}]`,
 		},
 		{
 			description: "get supports list_changed_files",
@@ -139,20 +140,79 @@ func TestGet(t *testing.T) {
 				},
 			},
 			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z"}`,
-			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"}]`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"draft","value":"false"}]`,
 			filesString:    "README.md\nOther.md\n",
 		},
+		{
+			description: "get adds the comment that triggered the version to the metadata",
+			source: resource.Source{
+				Repository:    "itsdalmo/test-repository",
+				AccessToken:   "oauthtoken",
+				TriggerPhrase: "^/retest",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+				Comment:       "101",
+			},
+			parameters:     resource.GetParameters{},
+			pullRequest:    createTestPR(1, "master", false, false, 0, nil),
+			comment:        &resource.CommentObject{DatabaseId: 101, Body: "/retest", Author: struct{ Login string }{Login: "collaborator"}},
+			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","comment":"101"}`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"draft","value":"false"},{"name":"trigger_comment","value":"/retest"},{"name":"trigger_comment_author","value":"collaborator"}]`,
+		},
+		{
+			description: "get checks out the merge group commit for merge queue versions",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				MergeQueue:  true,
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+				MergeGroup:    "group1",
+			},
+			parameters:     resource.GetParameters{},
+			pullRequest:    createTestPR(1, "master", false, false, 0, nil),
+			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","merge_group":"group1"}`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"draft","value":"false"},{"name":"merge_group_sha","value":"group1"}]`,
+		},
+		{
+			description: "get pulls the base commit of the version when tracking the base branch",
+			source: resource.Source{
+				Repository:      "itsdalmo/test-repository",
+				AccessToken:     "oauthtoken",
+				TrackBaseBranch: true,
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+				BaseCommit:    "base1",
+			},
+			parameters:     resource.GetParameters{},
+			pullRequest:    createTestPR(1, "master", false, false, 0, nil),
+			versionString:  `{"pr":"pr1","commit":"commit1","committed":"0001-01-01T00:00:00Z","base_commit":"base1"}`,
+			metadataString: `[{"name":"pr","value":"1"},{"name":"url","value":"pr1 url"},{"name":"head_name","value":"pr1"},{"name":"head_sha","value":"oid1"},{"name":"base_name","value":"master"},{"name":"base_sha","value":"sha"},{"name":"message","value":"commit message1"},{"name":"author","value":"login1"},{"name":"draft","value":"false"}]`,
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
-			github := new(fakes.FakeGithub)
+			github := new(fakes.FakeProvider)
 			github.GetPullRequestReturns(tc.pullRequest, nil)
 
 			if tc.files != nil {
 				github.GetChangedFilesReturns(tc.files, nil)
 			}
 
+			if tc.comment != nil {
+				github.GetCommentReturns(tc.comment, nil)
+			}
+
 			git := new(fakes.FakeGit)
 			git.RevParseReturns("sha", nil)
 
@@ -183,6 +243,7 @@ func TestGet(t *testing.T) {
 					"base_sha":  "sha",
 					"message":   "commit message1",
 					"author":    "login1",
+					"draft":     "false",
 				}
 
 				for filename, expected := range files {
@@ -194,6 +255,19 @@ func TestGet(t *testing.T) {
 					changedFiles := readTestFile(t, filepath.Join(dir, ".git", "resource", "changed_files"))
 					assert.Equal(t, tc.filesString, changedFiles)
 				}
+
+				if tc.comment != nil {
+					comment := readTestFile(t, filepath.Join(dir, ".git", "resource", "trigger_comment"))
+					assert.Equal(t, tc.comment.Body, comment)
+				}
+			}
+
+			if tc.comment != nil {
+				if assert.Equal(t, 1, github.GetCommentCallCount()) {
+					pr, id := github.GetCommentArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, tc.version.Comment, id)
+				}
 			}
 
 			// Validate Github calls
@@ -210,9 +284,13 @@ func TestGet(t *testing.T) {
 			}
 
 			if assert.Equal(t, 1, git.PullCallCount()) {
+				expected := tc.pullRequest.BaseRefName
+				if tc.version.BaseCommit != "" {
+					expected = tc.version.BaseCommit
+				}
 				url, base, depth := git.PullArgsForCall(0)
 				assert.Equal(t, tc.pullRequest.Repository.URL, url)
-				assert.Equal(t, tc.pullRequest.BaseRefName, base)
+				assert.Equal(t, expected, base)
 				assert.Equal(t, tc.parameters.GitDepth, depth)
 			}
 
@@ -228,14 +306,27 @@ func TestGet(t *testing.T) {
 				assert.Equal(t, tc.parameters.GitDepth, depth)
 			}
 
-			switch tc.parameters.IntegrationTool {
-			case "rebase":
+			switch {
+			case tc.version.MergeGroup != "":
+				if assert.Equal(t, 1, git.FetchRefCallCount()) {
+					url, ref, depth := git.FetchRefArgsForCall(0)
+					assert.Equal(t, tc.pullRequest.Repository.URL, url)
+					assert.Equal(t, tc.version.MergeGroup, ref)
+					assert.Equal(t, tc.parameters.GitDepth, depth)
+				}
+				if assert.Equal(t, 1, git.CheckoutCallCount()) {
+					branch, sha := git.CheckoutArgsForCall(0)
+					assert.Equal(t, "merge-group", branch)
+					assert.Equal(t, tc.version.MergeGroup, sha)
+				}
+				assert.Equal(t, 0, git.MergeCallCount())
+			case tc.parameters.IntegrationTool == "rebase":
 				if assert.Equal(t, 1, git.RebaseCallCount()) {
 					branch, tip := git.RebaseArgsForCall(0)
 					assert.Equal(t, tc.pullRequest.BaseRefName, branch)
 					assert.Equal(t, tc.pullRequest.Tip.OID, tip)
 				}
-			case "checkout":
+			case tc.parameters.IntegrationTool == "checkout":
 				if assert.Equal(t, 1, git.CheckoutCallCount()) {
 					branch, sha := git.CheckoutArgsForCall(0)
 					assert.Equal(t, tc.pullRequest.HeadRefName, branch)
@@ -282,7 +373,7 @@ func TestGetSkipDownload(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
-			github := new(fakes.FakeGithub)
+			github := new(fakes.FakeProvider)
 			git := new(fakes.FakeGit)
 			dir := createTestDirectory(t)
 			defer os.RemoveAll(dir)
@@ -335,6 +426,9 @@ func createTestPR(
 				URL: fmt.Sprintf("repo%s url", n),
 			},
 			IsCrossRepository: isCrossRepo,
+			Author: struct{ Login string }{
+				Login: fmt.Sprintf("login%s", n),
+			},
 		},
 		Tip: resource.CommitObject{
 			ID:            fmt.Sprintf("commit%s", n),
@@ -367,3 +461,73 @@ func readTestFile(t *testing.T, path string) string {
 	}
 	return string(b)
 }
+
+func TestGetMergeConflict(t *testing.T) {
+	tests := []struct {
+		description string
+		parameters  resource.GetParameters
+	}{
+		{
+			description: "get fails with the conflicting files",
+			parameters:  resource.GetParameters{},
+		},
+		{
+			description: "get comments on the pull request when specified",
+			parameters:  resource.GetParameters{ConflictComment: true},
+		},
+		{
+			description: "get sets a status on the commit when specified",
+			parameters:  resource.GetParameters{IntegrationTool: "rebase", ConflictStatus: true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			conflict := &resource.MergeConflictError{Files: []string{"README.md", "main.go"}}
+
+			github := new(fakes.FakeProvider)
+			github.GetPullRequestReturns(createTestPR(1, "master", false, false, 0, nil), nil)
+
+			git := new(fakes.FakeGit)
+			git.RevParseReturns("sha", nil)
+			git.MergeReturns(conflict)
+			git.RebaseReturns(conflict)
+
+			dir := createTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			input := resource.GetRequest{
+				Source:  resource.Source{Repository: "itsdalmo/test-repository", AccessToken: "oauthtoken"},
+				Version: resource.Version{PR: "pr1", Commit: "commit1"},
+				Params:  tc.parameters,
+			}
+			_, err := resource.Get(input, github, git, dir)
+			assert.EqualError(t, err, "merge conflict in: README.md, main.go")
+
+			if tc.parameters.ConflictComment {
+				if assert.Equal(t, 1, github.UpdateCommentCallCount()) {
+					pr, key, comment := github.UpdateCommentArgsForCall(0)
+					assert.Equal(t, "pr1", pr)
+					assert.Equal(t, "merge-conflict", key)
+					assert.Equal(t, "This pull request conflicts with `master` and needs a rebase. Conflicting files:\n\n- `README.md`\n- `main.go`", comment)
+				}
+			} else {
+				assert.Equal(t, 0, github.UpdateCommentCallCount())
+			}
+
+			if tc.parameters.ConflictStatus {
+				if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
+					commit, baseContext, context, status, targetURL, description := github.UpdateCommitStatusArgsForCall(0)
+					assert.Equal(t, "commit1", commit)
+					assert.Equal(t, "", baseContext)
+					assert.Equal(t, "merge-conflict", context)
+					assert.Equal(t, "failure", status)
+					assert.Equal(t, "", targetURL)
+					assert.Equal(t, "Conflicts with master, needs a rebase", description)
+				}
+			} else {
+				assert.Equal(t, 0, github.UpdateCommitStatusCallCount())
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package resource
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
 	"strconv"
 	"time"
 
@@ -10,35 +12,147 @@ import (
 
 // Source represents the configuration for the resource.
 type Source struct {
-	Repository              string   `json:"repository"`
-	AccessToken             string   `json:"access_token"`
-	V3Endpoint              string   `json:"v3_endpoint"`
-	V4Endpoint              string   `json:"v4_endpoint"`
-	Paths                   []string `json:"paths"`
-	IgnorePaths             []string `json:"ignore_paths"`
-	DisableCISkip           bool     `json:"disable_ci_skip"`
-	SkipSSLVerification     bool     `json:"skip_ssl_verification"`
-	DisableForks            bool     `json:"disable_forks"`
-	GitCryptKey             string   `json:"git_crypt_key"`
-	BaseBranch              string   `json:"base_branch"`
-	RequiredReviewApprovals int      `json:"required_review_approvals"`
-	Labels                  []string `json:"labels"`
+	Provider                string           `json:"provider"`
+	Endpoint                string           `json:"endpoint"`
+	Repository              string           `json:"repository"`
+	AccessToken             string           `json:"access_token"`
+	V3Endpoint              string           `json:"v3_endpoint"`
+	V4Endpoint              string           `json:"v4_endpoint"`
+	Paths                   []string         `json:"paths"`
+	IgnorePaths             []string         `json:"ignore_paths"`
+	DisableCISkip           bool             `json:"disable_ci_skip"`
+	SkipSSLVerification     bool             `json:"skip_ssl_verification"`
+	DisableForks            bool             `json:"disable_forks"`
+	GitCryptKey             string           `json:"git_crypt_key"`
+	BaseBranch              string           `json:"base_branch"`
+	RequiredReviewApprovals int              `json:"required_review_approvals"`
+	Labels                  []string         `json:"labels"`
+	GithubAppID             int64            `json:"github_app_id"`
+	GithubAppInstallationID int64            `json:"github_app_installation_id"`
+	GithubAppPrivateKey     string           `json:"github_app_private_key"`
+	MaxRetries              int              `json:"max_retries"`
+	MaxRetryWait            string           `json:"max_retry_wait"`
+	IgnoreDrafts            bool             `json:"ignore_drafts"`
+	AllowedAuthors          []string         `json:"allowed_authors"`
+	IgnoredAuthors          []string         `json:"ignored_authors"`
+	AllowedTeams            []string         `json:"allowed_teams"`
+	TriggerPhrase           string           `json:"trigger_phrase"`
+	MergeQueue              bool             `json:"merge_queue"`
+	EveryCommit             bool             `json:"every_commit"`
+	IgnoreConflicting       bool             `json:"ignore_conflicting"`
+	TrackBaseBranch         bool             `json:"track_base_branch"`
+	MaxBaseBranchRebuilds   int              `json:"max_base_branch_rebuilds"`
+	RequiredStatuses        []RequiredStatus `json:"required_statuses"`
+	IgnoreLabels            []string         `json:"ignore_labels"`
+	LabelsMatch             string           `json:"labels_match"`
+	PathsScope              string           `json:"paths_scope"`
+	CheckConcurrency        int              `json:"check_concurrency"`
+	HTTPCache               bool             `json:"http_cache"`
+	HTTPCacheDir            string           `json:"http_cache_dir"`
+}
+
+// RequiredStatus is a status (or check run) that the tip of a pull request must have.
+type RequiredStatus struct {
+	Context string `json:"context"`
+	State   string `json:"state"`
+}
+
+// Match returns true if the context matches the required context, which is a regular expression
+// that must match the whole name.
+func (r RequiredStatus) Match(context string) (bool, error) {
+	return regexp.MatchString("^(?:"+r.Context+")$", context)
 }
 
 // Validate the source configuration.
 func (s *Source) Validate() error {
-	if s.AccessToken == "" {
-		return errors.New("access_token must be set")
+	app := s.GithubAppID != 0 || s.GithubAppInstallationID != 0 || s.GithubAppPrivateKey != ""
+	if s.AccessToken == "" && !app {
+		return errors.New("access_token (or github_app_id) must be set")
+	}
+	if s.AccessToken != "" && app {
+		return errors.New("access_token cannot be set together with github_app_id")
+	}
+	if app && (s.GithubAppID == 0 || s.GithubAppInstallationID == 0 || s.GithubAppPrivateKey == "") {
+		return errors.New("github_app_id, github_app_installation_id and github_app_private_key must be set together")
 	}
 	if s.Repository == "" {
 		return errors.New("repository must be set")
 	}
+	if !isOneOf(s.ProviderName(), "github", "gitlab", "gitea", "forgejo") {
+		return fmt.Errorf("unknown provider: %s", s.Provider)
+	}
+	if isOneOf(s.ProviderName(), "gitea", "forgejo") && s.Endpoint == "" {
+		return fmt.Errorf("endpoint must be set for %s", s.ProviderName())
+	}
+	if s.ProviderName() == "github" && s.Endpoint != "" {
+		return errors.New("endpoint is not supported for github (use v3_endpoint and v4_endpoint)")
+	}
+	if s.ProviderName() != "github" {
+		if app {
+			return fmt.Errorf("github_app_id is not supported for %s", s.ProviderName())
+		}
+		if s.V3Endpoint != "" || s.V4Endpoint != "" {
+			return fmt.Errorf("v3_endpoint and v4_endpoint are not supported for %s (use endpoint)", s.ProviderName())
+		}
+		if s.MergeQueue {
+			return fmt.Errorf("merge_queue is not supported for %s", s.ProviderName())
+		}
+	}
 	if s.V3Endpoint != "" && s.V4Endpoint == "" {
 		return errors.New("v4_endpoint must be set together with v3_endpoint")
 	}
 	if s.V4Endpoint != "" && s.V3Endpoint == "" {
 		return errors.New("v3_endpoint must be set together with v4_endpoint")
 	}
+	for _, t := range s.AllowedTeams {
+		if _, _, err := parseTeam(t); err != nil {
+			return err
+		}
+	}
+	if s.TriggerPhrase != "" {
+		if _, err := regexp.Compile(s.TriggerPhrase); err != nil {
+			return fmt.Errorf("failed to compile trigger_phrase: %s", err)
+		}
+	}
+	if s.EveryCommit && s.MergeQueue {
+		return errors.New("every_commit cannot be combined with merge_queue")
+	}
+	if s.MaxBaseBranchRebuilds < 0 {
+		return errors.New("max_base_branch_rebuilds must be 0 (unlimited) or greater")
+	}
+	for _, r := range s.RequiredStatuses {
+		if r.Context == "" {
+			return errors.New("context must be set for required_statuses")
+		}
+		if _, err := r.Match(""); err != nil {
+			return fmt.Errorf("failed to compile required status context: %s", err)
+		}
+		if r.State != "" && !isOneOf(r.State, "success", "failure", "error", "pending", "neutral", "cancelled", "skipped", "timed_out", "action_required", "stale") {
+			return fmt.Errorf("unknown state for required status %s: %s", r.Context, r.State)
+		}
+	}
+	if s.LabelsMatch != "" && !isOneOf(s.LabelsMatch, "any", "all") {
+		return fmt.Errorf("unknown labels_match: %s", s.LabelsMatch)
+	}
+	for _, l := range s.IgnoreLabels {
+		if containsLabel(s.Labels, l) {
+			return fmt.Errorf("label %s cannot be both required and ignored", l)
+		}
+	}
+	if s.PathsScope != "" && !isOneOf(s.PathsScope, "pull_request", "latest_push") {
+		return fmt.Errorf("unknown paths_scope: %s", s.PathsScope)
+	}
+	if s.CheckConcurrency < 0 {
+		return errors.New("check_concurrency must be 0 (sequential) or greater")
+	}
+	if s.MaxRetries < -1 {
+		return errors.New("max_retries must be -1 (disabled), 0 (default) or greater")
+	}
+	if s.MaxRetryWait != "" {
+		if _, err := time.ParseDuration(s.MaxRetryWait); err != nil {
+			return fmt.Errorf("failed to parse max_retry_wait: %s", err)
+		}
+	}
 	return nil
 }
 
@@ -61,6 +175,9 @@ type Version struct {
 	PR            string    `json:"pr"`
 	Commit        string    `json:"commit"`
 	CommittedDate time.Time `json:"committed,omitempty"`
+	Comment       string    `json:"comment,omitempty"`
+	MergeGroup    string    `json:"merge_group,omitempty"`
+	BaseCommit    string    `json:"base_commit,omitempty"`
 }
 
 // NewVersion constructs a new Version.
@@ -78,6 +195,16 @@ type PullRequest struct {
 	Tip                 CommitObject
 	ApprovedReviewCount int
 	Labels              []LabelObject
+	ReadyForReviewTime  time.Time
+	PushedTime          time.Time
+	PushedAfter         time.Time // The latest time the head is known to have been pushed at or after.
+	PushesListed        bool      // Whether PushedAfter is based on all pushes to the head.
+	Comments            []CommentObject
+	MergeGroup          CommitObject
+	BaseTip             CommitObject
+	Statuses            []Status
+	LabelEvents         []LabelEvent
+	Files               []string // nil unless all of the changed files were listed.
 }
 
 // PullRequestObject represents the GraphQL commit node.
@@ -93,8 +220,40 @@ type PullRequestObject struct {
 		URL string
 	}
 	IsCrossRepository bool
+	IsDraft           bool
+	Mergeable         MergeableState
+	UpdatedAt         time.Time
+	Author            struct {
+		Login string
+	}
 }
 
+// MergeableState is whether a pull request can be merged, using the values of the Github enum.
+// It is UNKNOWN while the provider is still checking the pull request, and only CONFLICTING pull
+// requests are skipped by ignore_conflicting.
+// https://developer.github.com/v4/enum/mergeablestate/
+type MergeableState string
+
+// The mergeable states of a pull request.
+const (
+	MergeableStateMergeable   MergeableState = "MERGEABLE"
+	MergeableStateConflicting MergeableState = "CONFLICTING"
+	MergeableStateUnknown     MergeableState = "UNKNOWN"
+)
+
+// CommentAuthorAssociation is the relation of the author of a comment to the repository, using
+// the values of the Github enum.
+// https://developer.github.com/v4/enum/commentauthorassociation/
+type CommentAuthorAssociation string
+
+// The associations of comment authors that are used by the resource.
+const (
+	CommentAuthorAssociationOwner        CommentAuthorAssociation = "OWNER"
+	CommentAuthorAssociationMember       CommentAuthorAssociation = "MEMBER"
+	CommentAuthorAssociationCollaborator CommentAuthorAssociation = "COLLABORATOR"
+	CommentAuthorAssociationNone         CommentAuthorAssociation = "NONE"
+)
+
 // CommitObject represents the GraphQL commit node.
 // https://developer.github.com/v4/object/commit/
 type CommitObject struct {
@@ -109,6 +268,22 @@ type CommitObject struct {
 	}
 }
 
+// Status represents a commit status or check run on the tip of a pull request, where the
+// state of a check run is its conclusion (or pending, if it has not completed).
+// https://developer.github.com/v4/union/statuscheckrollupcontext/
+type Status struct {
+	Context   string
+	State     string
+	UpdatedAt time.Time
+}
+
+// LabelEvent represents a label being added to (or removed from) a pull request.
+type LabelEvent struct {
+	Name      string
+	Added     bool
+	CreatedAt time.Time
+}
+
 // ChangedFileObject represents the GraphQL FilesChanged node.
 // https://developer.github.com/v4/object/pullrequestchangedfile/
 type ChangedFileObject struct {
@@ -120,3 +295,65 @@ type ChangedFileObject struct {
 type LabelObject struct {
 	Name string
 }
+
+// CommentObject represents the GraphQL issue comment node.
+// https://developer.github.com/v4/object/issuecomment/
+type CommentObject struct {
+	DatabaseId        int64
+	Body              string
+	CreatedAt         time.Time
+	AuthorAssociation CommentAuthorAssociation
+	Author            struct {
+		Login string
+	}
+}
+
+// CheckRun represents a check run to create or update.
+// https://developer.github.com/v3/checks/runs/
+type CheckRun struct {
+	Name        string
+	ExternalID  string
+	Status      string
+	Conclusion  string
+	DetailsURL  string
+	Title       string
+	Summary     string
+	Text        string
+	Annotations []Annotation
+}
+
+// Annotation represents a line annotation on a check run.
+// https://developer.github.com/v3/checks/runs/#annotations-object
+type Annotation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Level     string `json:"level"`
+	Title     string `json:"title"`
+	Message   string `json:"message"`
+}
+
+// Review represents a pull request review to submit.
+// https://developer.github.com/v3/pulls/reviews/#create-a-pull-request-review
+type Review struct {
+	Event    string
+	Body     string
+	Comments []ReviewComment
+}
+
+// ReviewComment represents an inline comment in a pull request review.
+type ReviewComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Side string `json:"side,omitempty"`
+	Body string `json:"body"`
+}
+
+// Merge represents how a pull request should be merged.
+// https://developer.github.com/v3/pulls/#merge-a-pull-request-merge-button
+type Merge struct {
+	Method        string
+	CommitTitle   string
+	CommitMessage string
+	DeleteBranch  bool
+}
@@ -2,15 +2,17 @@ package resource
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"text/template"
 )
 
 // Put (business logic)
-func Put(request PutRequest, manager Github, inputDir string) (*PutResponse, error) {
+func Put(request PutRequest, manager Provider, inputDir string) (*PutResponse, error) {
 	if err := request.Params.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid parameters: %s", err)
 	}
@@ -36,6 +38,13 @@ func Put(request PutRequest, manager Github, inputDir string) (*PutResponse, err
 		return nil, fmt.Errorf("failed to unmarshal metadata from file: %s", err)
 	}
 
+	// Statuses and check runs for a merge group are set on the merge group commit, which is
+	// what the merge queue waits for.
+	commit := version.Commit
+	if version.MergeGroup != "" {
+		commit = version.MergeGroup
+	}
+
 	// Set status if specified
 	if p := request.Params; p.Status != "" {
 		description := p.Description
@@ -49,11 +58,140 @@ func Put(request PutRequest, manager Github, inputDir string) (*PutResponse, err
 			description = string(content)
 		}
 
-		if err := manager.UpdateCommitStatus(version.Commit, p.BaseContext, p.Context, p.Status, safeExpandEnv(p.TargetURL), description); err != nil {
+		if err := manager.UpdateCommitStatus(commit, p.BaseContext, p.Context, p.Status, safeExpandEnv(p.TargetURL), description); err != nil {
 			return nil, fmt.Errorf("failed to set status: %s", err)
 		}
 	}
 
+	// Create or update a check run if specified
+	if c := request.Params.CheckRun; c != nil {
+		run := CheckRun{
+			Name:        c.Name,
+			ExternalID:  os.Getenv("BUILD_ID"),
+			Status:      strings.ToLower(c.Status),
+			Conclusion:  strings.ToLower(c.Conclusion),
+			DetailsURL:  safeExpandEnv(c.DetailsURL),
+			Title:       c.Title,
+			Summary:     safeExpandEnv(c.Summary),
+			Text:        safeExpandEnv(c.Text),
+			Annotations: c.Annotations,
+		}
+		if run.Status == "" {
+			run.Status = "in_progress"
+			if run.Conclusion != "" {
+				run.Status = "completed"
+			}
+		}
+
+		// Set summary and text from files
+		if c.SummaryFile != "" {
+			content, err := ioutil.ReadFile(filepath.Join(inputDir, c.SummaryFile))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read check run summary file: %s", err)
+			}
+			run.Summary = safeExpandEnv(string(content))
+		}
+		if c.TextFile != "" {
+			content, err := ioutil.ReadFile(filepath.Join(inputDir, c.TextFile))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read check run text file: %s", err)
+			}
+			run.Text = safeExpandEnv(string(content))
+		}
+
+		// Add annotations from a report
+		if p := request.Params; p.AnnotationsFile != "" {
+			content, err := ioutil.ReadFile(filepath.Join(inputDir, p.AnnotationsFile))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read annotations file: %s", err)
+			}
+			annotations, err := ParseAnnotations(p.AnnotationsFormat, content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse annotations file: %s", err)
+			}
+			run.Annotations = append(run.Annotations, annotations...)
+		}
+		if err := RelativeAnnotationPaths(filepath.Join(inputDir, request.Params.Path), run.Annotations); err != nil {
+			return nil, fmt.Errorf("failed to resolve annotation paths: %s", err)
+		}
+
+		// Github requires both a title and summary for the output.
+		if run.Title == "" && (run.Summary != "" || run.Text != "" || len(run.Annotations) > 0) {
+			run.Title = run.Name
+		}
+		if run.Summary == "" && run.Title != "" {
+			run.Summary = run.Title
+		}
+
+		if err := manager.UpdateCheckRun(commit, run); err != nil {
+			return nil, fmt.Errorf("failed to update check run: %s", err)
+		}
+	}
+
+	// Submit a review if specified
+	if r := request.Params.Review; r != nil {
+		review := Review{
+			Event: strings.ToUpper(r.Event),
+			Body:  safeExpandEnv(r.Body),
+		}
+
+		// Set body from a file
+		if r.BodyFile != "" {
+			content, err := ioutil.ReadFile(filepath.Join(inputDir, r.BodyFile))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read review body file: %s", err)
+			}
+			review.Body = safeExpandEnv(string(content))
+		}
+
+		// Add inline comments from a file
+		if r.CommentsFile != "" {
+			content, err := ioutil.ReadFile(filepath.Join(inputDir, r.CommentsFile))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read review comments file: %s", err)
+			}
+			if err := json.Unmarshal(content, &review.Comments); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal review comments file: %s", err)
+			}
+			if err := validateReviewComments(review.Comments); err != nil {
+				return nil, fmt.Errorf("invalid review comments file: %s", err)
+			}
+		}
+
+		if review.Event != "APPROVE" && strings.TrimSpace(review.Body) == "" {
+			return nil, fmt.Errorf("a review body is required for %s", strings.ToLower(review.Event))
+		}
+
+		if err := manager.CreateReview(version.PR, version.Commit, review); err != nil {
+			return nil, fmt.Errorf("failed to create review: %s", err)
+		}
+	}
+
+	// Remove and add labels if specified
+	if p := request.Params; len(p.RemoveLabels) > 0 {
+		if err := manager.RemoveLabels(version.PR, p.RemoveLabels); err != nil {
+			return nil, fmt.Errorf("failed to remove labels: %s", err)
+		}
+	}
+
+	labels := request.Params.AddLabels
+	if p := request.Params; p.LabelsFile != "" {
+		content, err := ioutil.ReadFile(filepath.Join(inputDir, p.LabelsFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read labels file: %s", err)
+		}
+		for _, l := range strings.Split(string(content), "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				labels = append(labels, l)
+			}
+		}
+	}
+	if len(labels) > 0 {
+		if err := manager.AddLabels(version.PR, labels, request.Params.CreateMissingLabels); err != nil {
+			return nil, fmt.Errorf("failed to add labels: %s", err)
+		}
+	}
+
 	// Delete previous comments if specified
 	if request.Params.DeletePreviousComments {
 		err = manager.DeletePreviousComments(version.PR)
@@ -62,9 +200,17 @@ func Put(request PutRequest, manager Github, inputDir string) (*PutResponse, err
 		}
 	}
 
+	// Update the comment with the same key if specified, or post a new one
+	postComment := func(comment string) error {
+		if key := request.Params.CommentKey; key != "" {
+			return manager.UpdateComment(version.PR, key, comment)
+		}
+		return manager.PostComment(version.PR, comment)
+	}
+
 	// Set comment if specified
 	if p := request.Params; p.Comment != "" {
-		err = manager.PostComment(version.PR, safeExpandEnv(p.Comment))
+		err = postComment(safeExpandEnv(p.Comment))
 		if err != nil {
 			return nil, fmt.Errorf("failed to post comment: %s", err)
 		}
@@ -78,13 +224,52 @@ func Put(request PutRequest, manager Github, inputDir string) (*PutResponse, err
 		}
 		comment := string(content)
 		if comment != "" {
-			err = manager.PostComment(version.PR, safeExpandEnv(comment))
+			err = postComment(safeExpandEnv(comment))
 			if err != nil {
 				return nil, fmt.Errorf("failed to post comment: %s", err)
 			}
 		}
 	}
 
+	// Merge the pull request if specified
+	if m := request.Params.Merge; m != nil {
+		pull, err := manager.GetPullRequest(version.PR, version.Commit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pull request: %s", err)
+		}
+
+		merge := Merge{
+			Method:       strings.ToLower(m.Method),
+			DeleteBranch: m.DeleteBranch,
+		}
+		if merge.Method == "" {
+			merge.Method = "merge"
+		}
+		if merge.CommitTitle, err = renderMergeTemplate(m.CommitTitle, pull); err != nil {
+			return nil, fmt.Errorf("failed to render merge commit title: %s", err)
+		}
+		if merge.CommitMessage, err = renderMergeTemplate(m.CommitMessage, pull); err != nil {
+			return nil, fmt.Errorf("failed to render merge commit message: %s", err)
+		}
+
+		switch strings.ToLower(m.Mode) {
+		case "auto":
+			if err := manager.EnableAutoMerge(pull.ID, version.Commit, merge); err != nil {
+				return nil, fmt.Errorf("failed to enable auto-merge: %s", err)
+			}
+		case "queue":
+			if err := manager.EnqueuePullRequest(pull.ID, version.Commit); err != nil {
+				return nil, fmt.Errorf("failed to add pull request to the merge queue: %s", err)
+			}
+		default:
+			sha, err := manager.MergePullRequest(version.PR, version.Commit, merge)
+			if err != nil {
+				return nil, fmt.Errorf("failed to merge pull request: %s", err)
+			}
+			metadata.Add("merge_commit", sha)
+		}
+	}
+
 	return &PutResponse{
 		Version:  version,
 		Metadata: metadata,
@@ -105,42 +290,186 @@ type PutResponse struct {
 
 // PutParameters for the resource.
 type PutParameters struct {
-	Path                   string `json:"path"`
-	BaseContext            string `json:"base_context"`
-	Context                string `json:"context"`
-	TargetURL              string `json:"target_url"`
-	DescriptionFile        string `json:"description_file"`
-	Description            string `json:"description"`
-	Status                 string `json:"status"`
-	CommentFile            string `json:"comment_file"`
-	Comment                string `json:"comment"`
-	DeletePreviousComments bool   `json:"delete_previous_comments"`
+	Path                   string              `json:"path"`
+	BaseContext            string              `json:"base_context"`
+	Context                string              `json:"context"`
+	TargetURL              string              `json:"target_url"`
+	DescriptionFile        string              `json:"description_file"`
+	Description            string              `json:"description"`
+	Status                 string              `json:"status"`
+	CommentFile            string              `json:"comment_file"`
+	Comment                string              `json:"comment"`
+	CommentKey             string              `json:"comment_key"`
+	DeletePreviousComments bool                `json:"delete_previous_comments"`
+	CheckRun               *CheckRunParameters `json:"check_run"`
+	Review                 *ReviewParameters   `json:"review"`
+	Merge                  *MergeParameters    `json:"merge"`
+	AnnotationsFile        string              `json:"annotations_file"`
+	AnnotationsFormat      string              `json:"annotations_format"`
+	AddLabels              []string            `json:"add_labels"`
+	RemoveLabels           []string            `json:"remove_labels"`
+	LabelsFile             string              `json:"labels_file"`
+	CreateMissingLabels    bool                `json:"create_missing_labels"`
+}
+
+// ReviewParameters for submitting a pull request review.
+type ReviewParameters struct {
+	Event        string `json:"event"`
+	Body         string `json:"body"`
+	BodyFile     string `json:"body_file"`
+	CommentsFile string `json:"comments_file"`
+}
+
+// MergeParameters for merging the pull request.
+type MergeParameters struct {
+	Mode          string `json:"mode"`
+	Method        string `json:"method"`
+	CommitTitle   string `json:"commit_title"`
+	CommitMessage string `json:"commit_message"`
+	DeleteBranch  bool   `json:"delete_branch"`
+}
+
+// CheckRunParameters for creating or updating a check run.
+type CheckRunParameters struct {
+	Name        string       `json:"name"`
+	Status      string       `json:"status"`
+	Conclusion  string       `json:"conclusion"`
+	DetailsURL  string       `json:"details_url"`
+	Title       string       `json:"title"`
+	Summary     string       `json:"summary"`
+	SummaryFile string       `json:"summary_file"`
+	Text        string       `json:"text"`
+	TextFile    string       `json:"text_file"`
+	Annotations []Annotation `json:"annotations"`
 }
 
 // Validate the put parameters.
 func (p *PutParameters) Validate() error {
-	if p.Status == "" {
-		return nil
-	}
 	// Make sure we are setting an allowed status
-	var allowedStatus bool
+	if p.Status != "" && !isOneOf(p.Status, "success", "pending", "failure", "error") {
+		return fmt.Errorf("unknown status: %s", p.Status)
+	}
 
-	status := strings.ToLower(p.Status)
-	allowed := []string{"success", "pending", "failure", "error"}
+	if p.CommentKey != "" {
+		if strings.Contains(p.CommentKey, "--") {
+			return errors.New("comment_key cannot contain '--'")
+		}
+		if p.DeletePreviousComments {
+			return errors.New("comment_key cannot be combined with delete_previous_comments")
+		}
+		if p.Comment != "" && p.CommentFile != "" {
+			return errors.New("comment_key can only be used with one of comment or comment_file")
+		}
+	}
 
-	for _, a := range allowed {
-		if status == a {
-			allowedStatus = true
+	for _, add := range p.AddLabels {
+		for _, remove := range p.RemoveLabels {
+			if strings.EqualFold(add, remove) {
+				return fmt.Errorf("label '%s' cannot be both added and removed", add)
+			}
 		}
 	}
 
-	if !allowedStatus {
-		return fmt.Errorf("unknown status: %s", p.Status)
+	if p.CheckRun != nil {
+		if err := p.CheckRun.Validate(); err != nil {
+			return fmt.Errorf("invalid check run: %s", err)
+		}
+	}
+
+	if p.Review != nil {
+		if !isOneOf(p.Review.Event, "approve", "request_changes", "comment") {
+			return fmt.Errorf("unknown review event: %s", p.Review.Event)
+		}
+		if !strings.EqualFold(p.Review.Event, "approve") && p.Review.Body == "" && p.Review.BodyFile == "" {
+			return fmt.Errorf("review body or body_file must be set for %s", strings.ToLower(p.Review.Event))
+		}
+	}
+
+	if p.Merge != nil {
+		if p.Merge.Mode != "" && !isOneOf(p.Merge.Mode, "direct", "auto", "queue") {
+			return fmt.Errorf("unknown merge mode: %s", p.Merge.Mode)
+		}
+		if p.Merge.Method != "" && !isOneOf(p.Merge.Method, "merge", "squash", "rebase") {
+			return fmt.Errorf("unknown merge method: %s", p.Merge.Method)
+		}
+		if strings.EqualFold(p.Merge.Mode, "queue") && (p.Merge.Method != "" || p.Merge.CommitTitle != "" || p.Merge.CommitMessage != "") {
+			return errors.New("merge method and commit templates are set by the merge queue")
+		}
+		if p.Merge.DeleteBranch && isOneOf(p.Merge.Mode, "auto", "queue") {
+			return errors.New("delete_branch is only supported for direct merges")
+		}
+		for _, t := range []string{p.Merge.CommitTitle, p.Merge.CommitMessage} {
+			if _, err := template.New("merge").Parse(t); err != nil {
+				return fmt.Errorf("failed to parse merge template: %s", err)
+			}
+		}
+	}
+
+	if p.AnnotationsFile != "" {
+		if p.CheckRun == nil {
+			return errors.New("annotations_file requires check_run to be set")
+		}
+		if p.CheckRun.Conclusion == "" {
+			return errors.New("annotations_file can only be used when the check run is completed (conclusion is set)")
+		}
+		if !isOneOf(p.AnnotationsFormat, AnnotationFormats...) {
+			return fmt.Errorf("unknown annotations_format: %s (must be one of: %s)", p.AnnotationsFormat, strings.Join(AnnotationFormats, ", "))
+		}
 	}
 
 	return nil
 }
 
+// Validate the check run parameters and set defaults for annotations.
+func (c *CheckRunParameters) Validate() error {
+	if c.Name == "" {
+		return errors.New("name must be set")
+	}
+	if c.Status != "" && !isOneOf(c.Status, "queued", "in_progress", "completed") {
+		return fmt.Errorf("unknown status: %s", c.Status)
+	}
+	if c.Conclusion != "" && !isOneOf(c.Conclusion, "success", "failure", "neutral", "cancelled", "timed_out", "action_required") {
+		return fmt.Errorf("unknown conclusion: %s", c.Conclusion)
+	}
+	if strings.EqualFold(c.Status, "completed") && c.Conclusion == "" {
+		return errors.New("conclusion must be set when the status is completed")
+	}
+	if c.Conclusion != "" && c.Status != "" && !strings.EqualFold(c.Status, "completed") {
+		return errors.New("conclusion can only be set when the status is completed")
+	}
+	// Github appends annotations on every update, so they are only sent on completion.
+	if len(c.Annotations) > 0 && c.Conclusion == "" {
+		return errors.New("annotations can only be set when the check run is completed (conclusion is set)")
+	}
+	for i := range c.Annotations {
+		a := &c.Annotations[i]
+		if a.Path == "" || a.StartLine < 1 {
+			return fmt.Errorf("annotation %d: path and start_line must be set", i)
+		}
+		if a.EndLine < a.StartLine {
+			a.EndLine = a.StartLine
+		}
+		a.Level = strings.ToLower(a.Level)
+		if a.Level == "" {
+			a.Level = "warning"
+		}
+		if !isOneOf(a.Level, "notice", "warning", "failure") {
+			return fmt.Errorf("annotation %d: unknown level: %s", i, a.Level)
+		}
+	}
+	return nil
+}
+
+// isOneOf returns true if s is equal to one of the allowed values (case insensitive).
+func isOneOf(s string, allowed ...string) bool {
+	for _, a := range allowed {
+		if strings.EqualFold(s, a) {
+			return true
+		}
+	}
+	return false
+}
+
 func safeExpandEnv(s string) string {
 	return os.Expand(s, func(v string) string {
 		switch v {
@@ -150,3 +479,36 @@ func safeExpandEnv(s string) string {
 		return "$" + v
 	})
 }
+
+// renderMergeTemplate renders a commit title or message template for the pull request,
+// e.g. "{{.Title}} (#{{.Number}})".
+func renderMergeTemplate(text string, pull *PullRequest) (string, error) {
+	t, err := template.New("merge").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, pull); err != nil {
+		return "", err
+	}
+	return safeExpandEnv(b.String()), nil
+}
+
+// validateReviewComments makes sure all inline review comments can be placed
+// and normalises the side of the diff they are placed on.
+func validateReviewComments(comments []ReviewComment) error {
+	for i := range comments {
+		c := &comments[i]
+		if c.Path == "" || c.Line < 1 || c.Body == "" {
+			return fmt.Errorf("comment %d: path, line and body must be set", i)
+		}
+		c.Side = strings.ToUpper(c.Side)
+		if c.Side == "" {
+			c.Side = "RIGHT"
+		}
+		if c.Side != "LEFT" && c.Side != "RIGHT" {
+			return fmt.Errorf("comment %d: unknown side: %s", i, c.Side)
+		}
+	}
+	return nil
+}
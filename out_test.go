@@ -2,7 +2,9 @@ package resource_test
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -20,6 +22,13 @@ func TestPut(t *testing.T) {
 		version     resource.Version
 		parameters  resource.PutParameters
 		pullRequest *resource.PullRequest
+		inputFiles  map[string]string
+		checkRun    *resource.CheckRun
+		addedLabels []string
+		review      *resource.Review
+		merge       *resource.Merge
+		autoMerge   *resource.Merge
+		enqueue     bool
 	}{
 		{
 			description: "put with no parameters does nothing",
@@ -143,6 +152,24 @@ func TestPut(t *testing.T) {
 			pullRequest: createTestPR(1, "master", false, false, 0, nil),
 		},
 
+		{
+			description: "we can update a comment on the pull request by key",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Comment:    "comment",
+				CommentKey: "terraform-plan",
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil),
+		},
+
 		{
 			description: "we can delete previous comments made on the pull request",
 			source: resource.Source{
@@ -159,11 +186,292 @@ func TestPut(t *testing.T) {
 			},
 			pullRequest: createTestPR(1, "master", false, false, 0, []string{}),
 		},
+
+		{
+			description: "we can create a check run with annotations",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				CheckRun: &resource.CheckRunParameters{
+					Name:       "unit-test",
+					Conclusion: "FAILURE",
+					Summary:    "1 test failed",
+					Annotations: []resource.Annotation{
+						{Path: "main.go", StartLine: 10, Message: "expected 1, got 2"},
+					},
+				},
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil),
+			checkRun: &resource.CheckRun{
+				Name:       "unit-test",
+				Status:     "completed",
+				Conclusion: "failure",
+				Title:      "unit-test",
+				Summary:    "1 test failed",
+				Annotations: []resource.Annotation{
+					{Path: "main.go", StartLine: 10, EndLine: 10, Level: "warning", Message: "expected 1, got 2"},
+				},
+			},
+		},
+
+		{
+			description: "we can set a check run in progress",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				CheckRun: &resource.CheckRunParameters{
+					Name:  "unit-test",
+					Title: "Running unit tests",
+				},
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil),
+			checkRun: &resource.CheckRun{
+				Name:    "unit-test",
+				Status:  "in_progress",
+				Title:   "Running unit tests",
+				Summary: "Running unit tests",
+			},
+		},
+		{
+			description: "we can add annotations to a check run from a report",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				CheckRun: &resource.CheckRunParameters{
+					Name:       "lint",
+					Conclusion: "failure",
+				},
+				AnnotationsFile:   "reports/checkstyle.xml",
+				AnnotationsFormat: "checkstyle",
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil),
+			inputFiles: map[string]string{
+				"reports/checkstyle.xml": `<checkstyle><file name="main.go"><error line="3" severity="error" message="unused variable" source="vet"/></file></checkstyle>`,
+			},
+			checkRun: &resource.CheckRun{
+				Name:       "lint",
+				Status:     "completed",
+				Conclusion: "failure",
+				Title:      "lint",
+				Summary:    "lint",
+				Annotations: []resource.Annotation{
+					{Path: "main.go", StartLine: 3, EndLine: 3, Level: "failure", Title: "vet", Message: "unused variable"},
+				},
+			},
+		},
+
+		{
+			description: "we can add annotations from a report with absolute paths",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				CheckRun: &resource.CheckRunParameters{
+					Name:       "lint",
+					Conclusion: "failure",
+				},
+				AnnotationsFile:   "reports/checkstyle.xml",
+				AnnotationsFormat: "checkstyle",
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil),
+			inputFiles: map[string]string{
+				"cmd/main.go": "package main",
+				"reports/checkstyle.xml": `<checkstyle>
+					<file name="/tmp/build/1a2b3c/pull-request/cmd/main.go"><error line="3" severity="error" message="unused variable" source="vet"/></file>
+					<file name="file:///tmp/build/1a2b3c/pull-request/cmd/main.go"><error line="4" severity="error" message="unused import" source="vet"/></file>
+					<file name="/tmp/build/1a2b3c/pull-request/deleted.go"><error line="5" severity="error" message="unused function" source="vet"/></file>
+				</checkstyle>`,
+			},
+			checkRun: &resource.CheckRun{
+				Name:       "lint",
+				Status:     "completed",
+				Conclusion: "failure",
+				Title:      "lint",
+				Summary:    "lint",
+				Annotations: []resource.Annotation{
+					{Path: "cmd/main.go", StartLine: 3, EndLine: 3, Level: "failure", Title: "vet", Message: "unused variable"},
+					{Path: "cmd/main.go", StartLine: 4, EndLine: 4, Level: "failure", Title: "vet", Message: "unused import"},
+					{Path: "/tmp/build/1a2b3c/pull-request/deleted.go", StartLine: 5, EndLine: 5, Level: "failure", Title: "vet", Message: "unused function"},
+				},
+			},
+		},
+
+		{
+			description: "we can add and remove labels",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				AddLabels:           []string{"ci:passed"},
+				RemoveLabels:        []string{"ci:pending"},
+				LabelsFile:          "labels.txt",
+				CreateMissingLabels: true,
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil),
+			inputFiles: map[string]string{
+				"labels.txt": "deploy:staging\n\n  needs-review \n",
+			},
+			addedLabels: []string{"ci:passed", "deploy:staging", "needs-review"},
+		},
+
+		{
+			description: "we can submit a review with inline comments",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Review: &resource.ReviewParameters{
+					Event:        "request_changes",
+					BodyFile:     "review/body.md",
+					CommentsFile: "review/comments.json",
+				},
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil),
+			inputFiles: map[string]string{
+				"review/body.md":       "Policy check failed",
+				"review/comments.json": `[{"path":"main.tf","line":3,"body":"Buckets must be private"},{"path":"old.tf","line":7,"side":"left","body":"Why was this removed?"}]`,
+			},
+			review: &resource.Review{
+				Event: "REQUEST_CHANGES",
+				Body:  "Policy check failed",
+				Comments: []resource.ReviewComment{
+					{Path: "main.tf", Line: 3, Side: "RIGHT", Body: "Buckets must be private"},
+					{Path: "old.tf", Line: 7, Side: "LEFT", Body: "Why was this removed?"},
+				},
+			},
+		},
+
+		{
+			description: "we can merge the pull request",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Merge: &resource.MergeParameters{
+					Method:        "SQUASH",
+					CommitTitle:   "{{.Title}} (#{{.Number}})",
+					CommitMessage: "Merged by {{.HeadRefName}}",
+					DeleteBranch:  true,
+				},
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil),
+			merge: &resource.Merge{
+				Method:        "squash",
+				CommitTitle:   "pr1 title (#1)",
+				CommitMessage: "Merged by pr1",
+				DeleteBranch:  true,
+			},
+		},
+
+		{
+			description: "we can enable auto-merge for the pull request",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Merge: &resource.MergeParameters{
+					Mode:   "auto",
+					Method: "squash",
+				},
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil),
+			autoMerge:   &resource.Merge{Method: "squash"},
+		},
+
+		{
+			description: "we can add the pull request to the merge queue",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+			},
+			parameters: resource.PutParameters{
+				Merge: &resource.MergeParameters{
+					Mode: "queue",
+				},
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil),
+			enqueue:     true,
+		},
+
+		{
+			description: "we set the status on the merge group commit for merge queue versions",
+			source: resource.Source{
+				Repository:  "itsdalmo/test-repository",
+				AccessToken: "oauthtoken",
+				MergeQueue:  true,
+			},
+			version: resource.Version{
+				PR:            "pr1",
+				Commit:        "commit1",
+				CommittedDate: time.Time{},
+				MergeGroup:    "group1",
+			},
+			parameters: resource.PutParameters{
+				Status: "success",
+			},
+			pullRequest: createTestPR(1, "master", false, false, 0, nil),
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
-			github := new(fakes.FakeGithub)
+			github := new(fakes.FakeProvider)
 			github.GetPullRequestReturns(tc.pullRequest, nil)
 
 			git := new(fakes.FakeGit)
@@ -178,6 +486,12 @@ func TestPut(t *testing.T) {
 			_, err := resource.Get(getInput, github, git, dir)
 			require.NoError(t, err)
 
+			for name, content := range tc.inputFiles {
+				path := filepath.Join(dir, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+				require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+			}
+
 			putInput := resource.PutRequest{Source: tc.source, Params: tc.parameters}
 			output, err := resource.Put(putInput, github, dir)
 
@@ -187,10 +501,15 @@ func TestPut(t *testing.T) {
 			}
 
 			// Validate method calls put on Github.
+			expectedCommit := tc.version.Commit
+			if tc.version.MergeGroup != "" {
+				expectedCommit = tc.version.MergeGroup
+			}
+
 			if tc.parameters.Status != "" {
 				if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
 					commit, baseContext, context, status, targetURL, description := github.UpdateCommitStatusArgsForCall(0)
-					assert.Equal(t, tc.version.Commit, commit)
+					assert.Equal(t, expectedCommit, commit)
 					assert.Equal(t, tc.parameters.BaseContext, baseContext)
 					assert.Equal(t, tc.parameters.Context, context)
 					assert.Equal(t, tc.parameters.TargetURL, targetURL)
@@ -199,7 +518,15 @@ func TestPut(t *testing.T) {
 				}
 			}
 
-			if tc.parameters.Comment != "" {
+			if tc.parameters.Comment != "" && tc.parameters.CommentKey != "" {
+				assert.Equal(t, 0, github.PostCommentCallCount())
+				if assert.Equal(t, 1, github.UpdateCommentCallCount()) {
+					pr, key, comment := github.UpdateCommentArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, tc.parameters.CommentKey, key)
+					assert.Equal(t, tc.parameters.Comment, comment)
+				}
+			} else if tc.parameters.Comment != "" {
 				if assert.Equal(t, 1, github.PostCommentCallCount()) {
 					pr, comment := github.PostCommentArgsForCall(0)
 					assert.Equal(t, tc.version.PR, pr)
@@ -213,6 +540,178 @@ func TestPut(t *testing.T) {
 					assert.Equal(t, tc.version.PR, pr)
 				}
 			}
+
+			if len(tc.parameters.RemoveLabels) > 0 {
+				if assert.Equal(t, 1, github.RemoveLabelsCallCount()) {
+					pr, labels := github.RemoveLabelsArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, tc.parameters.RemoveLabels, labels)
+				}
+			}
+
+			if tc.addedLabels != nil {
+				if assert.Equal(t, 1, github.AddLabelsCallCount()) {
+					pr, labels, create := github.AddLabelsArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, tc.addedLabels, labels)
+					assert.Equal(t, tc.parameters.CreateMissingLabels, create)
+				}
+			}
+
+			if tc.review != nil {
+				if assert.Equal(t, 1, github.CreateReviewCallCount()) {
+					pr, commit, review := github.CreateReviewArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, tc.version.Commit, commit)
+					assert.Equal(t, *tc.review, review)
+				}
+			}
+
+			if tc.merge != nil {
+				if assert.Equal(t, 1, github.MergePullRequestCallCount()) {
+					pr, commit, merge := github.MergePullRequestArgsForCall(0)
+					assert.Equal(t, tc.version.PR, pr)
+					assert.Equal(t, tc.version.Commit, commit)
+					assert.Equal(t, *tc.merge, merge)
+				}
+			}
+
+			if tc.autoMerge != nil {
+				assert.Equal(t, 0, github.MergePullRequestCallCount())
+				if assert.Equal(t, 1, github.EnableAutoMergeCallCount()) {
+					id, commit, merge := github.EnableAutoMergeArgsForCall(0)
+					assert.Equal(t, tc.pullRequest.ID, id)
+					assert.Equal(t, tc.version.Commit, commit)
+					assert.Equal(t, *tc.autoMerge, merge)
+				}
+			}
+
+			if tc.enqueue {
+				assert.Equal(t, 0, github.MergePullRequestCallCount())
+				if assert.Equal(t, 1, github.EnqueuePullRequestCallCount()) {
+					id, commit := github.EnqueuePullRequestArgsForCall(0)
+					assert.Equal(t, tc.pullRequest.ID, id)
+					assert.Equal(t, tc.version.Commit, commit)
+				}
+			}
+
+			if tc.checkRun != nil {
+				if assert.Equal(t, 1, github.UpdateCheckRunCallCount()) {
+					commit, run := github.UpdateCheckRunArgsForCall(0)
+					assert.Equal(t, expectedCommit, commit)
+					assert.Equal(t, *tc.checkRun, run)
+				}
+			}
+		})
+	}
+}
+
+func TestPutParametersValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		parameters  resource.PutParameters
+		expected    string
+	}{
+		{
+			description: "allows known statuses",
+			parameters:  resource.PutParameters{Status: "SUCCESS"},
+		},
+		{
+			description: "rejects unknown statuses",
+			parameters:  resource.PutParameters{Status: "done"},
+			expected:    "unknown status: done",
+		},
+		{
+			description: "requires a name for check runs",
+			parameters:  resource.PutParameters{CheckRun: &resource.CheckRunParameters{Status: "queued"}},
+			expected:    "invalid check run: name must be set",
+		},
+		{
+			description: "requires a conclusion for completed check runs",
+			parameters:  resource.PutParameters{CheckRun: &resource.CheckRunParameters{Name: "unit-test", Status: "completed"}},
+			expected:    "invalid check run: conclusion must be set when the status is completed",
+		},
+		{
+			description: "rejects annotations without a line",
+			parameters: resource.PutParameters{CheckRun: &resource.CheckRunParameters{
+				Name:        "unit-test",
+				Conclusion:  "failure",
+				Annotations: []resource.Annotation{{Path: "main.go"}},
+			}},
+			expected: "invalid check run: annotation 0: path and start_line must be set",
+		},
+		{
+			description: "rejects annotations before the check run is completed",
+			parameters: resource.PutParameters{CheckRun: &resource.CheckRunParameters{
+				Name:        "unit-test",
+				Status:      "in_progress",
+				Annotations: []resource.Annotation{{Path: "main.go", StartLine: 1}},
+			}},
+			expected: "invalid check run: annotations can only be set when the check run is completed (conclusion is set)",
+		},
+		{
+			description: "rejects annotation files before the check run is completed",
+			parameters: resource.PutParameters{
+				CheckRun:          &resource.CheckRunParameters{Name: "unit-test"},
+				AnnotationsFile:   "report.sarif",
+				AnnotationsFormat: "sarif",
+			},
+			expected: "annotations_file can only be used when the check run is completed (conclusion is set)",
+		},
+		{
+			description: "rejects comment keys combined with deleting previous comments",
+			parameters:  resource.PutParameters{Comment: "comment", CommentKey: "plan", DeletePreviousComments: true},
+			expected:    "comment_key cannot be combined with delete_previous_comments",
+		},
+		{
+			description: "rejects comment keys that would end the marker",
+			parameters:  resource.PutParameters{Comment: "comment", CommentKey: "plan-->"},
+			expected:    "comment_key cannot contain '--'",
+		},
+		{
+			description: "rejects labels that are both added and removed",
+			parameters:  resource.PutParameters{AddLabels: []string{"ci:passed"}, RemoveLabels: []string{"CI:passed"}},
+			expected:    "label 'ci:passed' cannot be both added and removed",
+		},
+		{
+			description: "allows approving without a body",
+			parameters:  resource.PutParameters{Review: &resource.ReviewParameters{Event: "APPROVE"}},
+		},
+		{
+			description: "rejects unknown review events",
+			parameters:  resource.PutParameters{Review: &resource.ReviewParameters{Event: "dismiss"}},
+			expected:    "unknown review event: dismiss",
+		},
+		{
+			description: "requires a body when requesting changes",
+			parameters:  resource.PutParameters{Review: &resource.ReviewParameters{Event: "request_changes"}},
+			expected:    "review body or body_file must be set for request_changes",
+		},
+		{
+			description: "rejects unknown merge methods",
+			parameters:  resource.PutParameters{Merge: &resource.MergeParameters{Method: "fast-forward"}},
+			expected:    "unknown merge method: fast-forward",
+		},
+		{
+			description: "rejects merge templates for the merge queue",
+			parameters:  resource.PutParameters{Merge: &resource.MergeParameters{Mode: "queue", CommitTitle: "{{.Title}}"}},
+			expected:    "merge method and commit templates are set by the merge queue",
+		},
+		{
+			description: "rejects deleting the branch for auto-merge",
+			parameters:  resource.PutParameters{Merge: &resource.MergeParameters{Mode: "auto", DeleteBranch: true}},
+			expected:    "delete_branch is only supported for direct merges",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			err := tc.parameters.Validate()
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
 		})
 	}
 }
@@ -293,7 +792,7 @@ func TestVariableSubstitution(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
-			github := new(fakes.FakeGithub)
+			github := new(fakes.FakeProvider)
 			github.GetPullRequestReturns(tc.pullRequest, nil)
 
 			git := new(fakes.FakeGit)
@@ -0,0 +1,66 @@
+package resource
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// Provider of pull requests (e.g. Github), for testing purposes.
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -o fakes/fake_provider.go . Provider
+type Provider interface {
+	ListOpenPullRequests() ([]*PullRequest, error)
+	ListModifiedFiles(int) ([]string, error)
+	ListModifiedFilesBetween(string, string) ([]string, error)
+	ListPullRequestCommits(int, string) ([]CommitObject, error)
+	GetPullRequestCommitBefore(int, time.Time) (string, error)
+	PostComment(string, string) error
+	UpdateComment(string, string, string) error
+	GetPullRequest(string, string) (*PullRequest, error)
+	GetChangedFiles(string, string) ([]ChangedFileObject, error)
+	UpdateCommitStatus(string, string, string, string, string, string) error
+	UpdateCheckRun(string, CheckRun) error
+	CreateReview(string, string, Review) error
+	MergePullRequest(string, string, Merge) (string, error)
+	EnableAutoMerge(string, string, Merge) error
+	EnqueuePullRequest(string, string) error
+	DeletePreviousComments(string) error
+	AddLabels(string, []string, bool) error
+	RemoveLabels(string, []string) error
+	GetComment(string, string) (*CommentObject, error)
+	ListTeamMembers(string, string) ([]string, error)
+}
+
+// NewProvider returns the client for the provider in the source configuration, which
+// uses a token source for the source configuration if tokenSource is nil.
+func NewProvider(s *Source, tokenSource oauth2.TokenSource) (Provider, error) {
+	switch s.ProviderName() {
+	case "gitlab":
+		client, err := NewGitlabClient(s, tokenSource)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case "gitea", "forgejo":
+		client, err := NewGiteaClient(s, tokenSource)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	default:
+		client, err := NewGithubClient(s, tokenSource)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+}
+
+// ProviderName returns the (lower case) name of the provider, which defaults to github.
+func (s *Source) ProviderName() string {
+	if s.Provider == "" {
+		return "github"
+	}
+	return strings.ToLower(s.Provider)
+}
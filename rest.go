@@ -0,0 +1,174 @@
+package resource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// restClient for handling requests to the REST APIs of providers other than Github.
+type restClient struct {
+	endpoint *url.URL
+	client   *http.Client
+	// perPage is the name of the query parameter for the number of items per page.
+	perPage  string
+	pageSize int
+}
+
+func newRESTClient(s *Source, tokenSource oauth2.TokenSource, endpoint, perPage string, pageSize int) (*restClient, error) {
+	if !strings.HasSuffix(endpoint, "/") {
+		endpoint += "/"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse endpoint: %s", err)
+	}
+
+	httpClient := newHTTPClient(s)
+	if tokenSource == nil {
+		if tokenSource, err = NewTokenSource(s, httpClient); err != nil {
+			return nil, err
+		}
+	}
+	ctx := context.WithValue(context.TODO(), oauth2.HTTPClient, httpClient)
+
+	return &restClient{
+		endpoint: u,
+		client:   oauth2.NewClient(ctx, tokenSource),
+		perPage:  perPage,
+		pageSize: pageSize,
+	}, nil
+}
+
+// APIError is returned for responses with an unexpected status code.
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, e.Message)
+}
+
+// do sends a request to the API, with the body encoded as JSON, and decodes the response into
+// out (if set). The response is returned with any error, to check the status code.
+func (c *restClient) do(method, p string, query url.Values, body, out interface{}) (*http.Response, error) {
+	ref, err := url.Parse(p)
+	if err != nil {
+		return nil, err
+	}
+	u := c.endpoint.ResolveReference(ref)
+	u.RawQuery = query.Encode()
+
+	var buf bytes.Buffer
+	if body != nil {
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(body); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(method, u.String(), &buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var message struct {
+			Message interface{} `json:"message"`
+			Error   string      `json:"error"`
+		}
+		text := strings.TrimSpace(string(b))
+		if json.Unmarshal(b, &message) == nil {
+			if message.Message != nil {
+				text = fmt.Sprint(message.Message)
+			} else if message.Error != "" {
+				text = message.Error
+			}
+		}
+		return resp, &APIError{Method: method, URL: u.Path, StatusCode: resp.StatusCode, Message: text}
+	}
+	if out != nil && len(b) > 0 {
+		if err := json.Unmarshal(b, out); err != nil {
+			return resp, fmt.Errorf("failed to unmarshal response: %s", err)
+		}
+	}
+	return resp, nil
+}
+
+// list requests the pages of a list, decoding each page into out (a pointer to a slice) and
+// calling fn, until fn returns false or there are no more pages.
+func (c *restClient) list(p string, query url.Values, out interface{}, fn func() bool) error {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	if q.Get(c.perPage) == "" {
+		q.Set(c.perPage, strconv.Itoa(c.pageSize))
+	}
+
+	for {
+		// Reset the page, since decoding reuses the elements of the previous page.
+		v := reflect.ValueOf(out).Elem()
+		v.Set(reflect.Zero(v.Type()))
+
+		resp, err := c.do(http.MethodGet, p, q, nil, out)
+		if err != nil {
+			return err
+		}
+		if !fn() {
+			return nil
+		}
+		next := nextPage(resp)
+		if next == "" {
+			return nil
+		}
+		q.Set("page", next)
+	}
+}
+
+// nextPage returns the next page of a list from the X-Next-Page header (Gitlab) or the
+// Link header (Gitea), or an empty string if this is the last page.
+func nextPage(resp *http.Response) string {
+	if next := resp.Header.Get("X-Next-Page"); next != "" {
+		return next
+	}
+	for _, link := range strings.Split(resp.Header.Get("Link"), ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 || strings.TrimSpace(parts[1]) != `rel="next"` {
+			continue
+		}
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(parts[0]), "<>"))
+		if err != nil {
+			return ""
+		}
+		return u.Query().Get("page")
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package resource_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// apiStandIn is a minimal stand-in for the REST APIs of Gitlab and Gitea. It serves the
+// responses by method and escaped path (e.g. "GET /api/v4/user"), where the page is part of
+// the path for pages after the first (e.g. "GET /api/v4/user?page=2"), and records the requests
+// (with their bodies and queries) it receives. Responses starting with a status code (e.g. "409 Conflict") are returned as errors.
+type apiStandIn struct {
+	responses map[string]string
+	requests  []string
+	bodies    map[string]string
+	queries   map[string]url.Values
+	// links paginates using the Link header (Gitea) instead of X-Next-Page (Gitlab).
+	links bool
+}
+
+func newAPIStandIn(t *testing.T, responses map[string]string) (*apiStandIn, *httptest.Server) {
+	g := &apiStandIn{responses: responses, bodies: make(map[string]string), queries: make(map[string]url.Values)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer oauthtoken", r.Header.Get("Authorization"))
+
+		key := r.Method + " " + r.URL.EscapedPath()
+		if page := r.URL.Query().Get("page"); page != "" {
+			key += "?page=" + page
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		g.requests = append(g.requests, key)
+		g.bodies[key] = strings.TrimSpace(string(body))
+		g.queries[key] = r.URL.Query()
+
+		response, ok := g.responses[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"404 Not Found"}`)
+			return
+		}
+		if _, ok := g.responses[key+"?page=2"]; ok {
+			if g.links {
+				w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?limit=50&page=2>; rel="next", <http://%s%s?limit=50&page=2>; rel="last"`, r.Host, r.URL.Path, r.Host, r.URL.Path))
+			} else {
+				w.Header().Set("X-Next-Page", "2")
+			}
+		}
+		var status int
+		if _, err := fmt.Sscanf(response, "%d ", &status); err == nil && status >= 400 {
+			w.WriteHeader(status)
+			fmt.Fprintf(w, `{"message":"%s"}`, response[4:])
+			return
+		}
+		fmt.Fprint(w, response)
+	}))
+	return g, server
+}
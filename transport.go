@@ -0,0 +1,229 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxRetries   = 3
+	defaultMaxRetryWait = 2 * time.Minute
+	defaultMinBackoff   = time.Second
+)
+
+// RetryTransport retries requests to the Github APIs that fail because of
+// (secondary) rate limits or transient server errors. Waits honour the
+// Retry-After and X-RateLimit-Reset headers when present, and otherwise
+// back off exponentially. Server errors are only retried for requests that
+// are safe to repeat, since Github may have applied a change (e.g. posted a
+// comment) before the gateway failed.
+type RetryTransport struct {
+	// Base is the transport used to make requests (defaults to http.DefaultTransport).
+	Base http.RoundTripper
+	// MaxRetries is the number of times a request is retried.
+	MaxRetries int
+	// MaxWait is the total time we are willing to wait across all retries of a request.
+	MaxWait time.Duration
+	// MinBackoff is the wait before the first retry when the response does not say how long to wait.
+	MinBackoff time.Duration
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var waited time.Duration
+	for attempt := 0; ; attempt++ {
+		r, err := rewindRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := t.base().RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+
+		wait, reason, err := t.retryAfter(r, resp, attempt)
+		if err != nil || reason == "" {
+			return resp, err
+		}
+		resp.Body.Close()
+
+		if attempt >= t.MaxRetries {
+			return nil, fmt.Errorf("giving up after %d retries: %s", attempt, reason)
+		}
+		if waited+wait > t.MaxWait {
+			return nil, fmt.Errorf("giving up after %d retries: %s: next attempt in %s would exceed the max retry wait of %s", attempt, reason, wait, t.MaxWait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		waited += wait
+	}
+}
+
+// retryAfter returns how long to wait before retrying, and the reason for
+// doing so. An empty reason means that the response should not be retried.
+func (t *RetryTransport) retryAfter(req *http.Request, resp *http.Response, attempt int) (time.Duration, string, error) {
+	backoff := t.MinBackoff << uint(attempt)
+
+	switch resp.StatusCode {
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return wait, fmt.Sprintf("rate limited (%s)", resp.Status), nil
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			if wait, ok := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset")); ok {
+				return wait, fmt.Sprintf("rate limit exceeded (%s)", resp.Status), nil
+			}
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return backoff, fmt.Sprintf("rate limited (%s)", resp.Status), nil
+		}
+		// Secondary rate limits do not always come with headers.
+		body, err := peekBody(resp)
+		if err != nil {
+			return 0, "", err
+		}
+		if strings.Contains(strings.ToLower(string(body)), "rate limit") {
+			return backoff, fmt.Sprintf("rate limited (%s)", resp.Status), nil
+		}
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		safe, err := isSafeRequest(req)
+		if err != nil || !safe {
+			return 0, "", err
+		}
+		return backoff, fmt.Sprintf("server error (%s)", resp.Status), nil
+	case http.StatusOK:
+		// The V4 API reports rate limiting as an error in the GraphQL response.
+		if !strings.HasSuffix(req.URL.Path, "graphql") {
+			break
+		}
+		body, err := peekBody(resp)
+		if err != nil {
+			return 0, "", err
+		}
+		var response struct {
+			Errors []struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			break
+		}
+		for _, e := range response.Errors {
+			if e.Type != "RATE_LIMITED" {
+				continue
+			}
+			if wait, ok := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset")); ok {
+				return wait, fmt.Sprintf("graphql rate limited: %s", e.Message), nil
+			}
+			return backoff, fmt.Sprintf("graphql rate limited: %s", e.Message), nil
+		}
+	}
+	return 0, "", nil
+}
+
+// isSafeRequest returns true if the request does not change anything, i.e. a GET or
+// HEAD request, or a GraphQL query (as opposed to a mutation).
+func isSafeRequest(req *http.Request) (bool, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true, nil
+	case http.MethodPost:
+		if !strings.HasSuffix(req.URL.Path, "graphql") || req.GetBody == nil {
+			return false, nil
+		}
+	default:
+		return false, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false, err
+	}
+	defer body.Close()
+	var request struct {
+		Query string `json:"query"`
+	}
+	if err := json.NewDecoder(body).Decode(&request); err != nil {
+		return false, nil
+	}
+	query := strings.TrimSpace(request.Query)
+	return strings.HasPrefix(query, "query") || strings.HasPrefix(query, "{"), nil
+}
+
+func (t *RetryTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// rewindRequest returns a request that can be sent for the given attempt.
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("unable to retry request: body cannot be rewound")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Body = body
+	return r, nil
+}
+
+// peekBody reads the response body and replaces it so it can be read again.
+func peekBody(resp *http.Response) ([]byte, error) {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// parseRetryAfter parses a Retry-After header (in seconds or as a HTTP date).
+func parseRetryAfter(s string) (time.Duration, bool) {
+	if s == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(s); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(s); err == nil {
+		return nonNegative(time.Until(t)), true
+	}
+	return 0, false
+}
+
+// parseRateLimitReset parses a X-RateLimit-Reset header (UTC epoch seconds).
+func parseRateLimitReset(s string) (time.Duration, bool) {
+	epoch, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	// Add a second since the reset time is truncated.
+	return nonNegative(time.Until(time.Unix(epoch, 0).Add(time.Second))), true
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,269 @@
+package resource_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	resource "github.com/telia-oss/github-pr-resource"
+)
+
+type testResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+func TestRetryTransport(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)
+
+	tests := []struct {
+		description   string
+		method        string
+		path          string
+		body          string
+		maxRetries    int
+		maxWait       time.Duration
+		responses     []testResponse
+		expectedCalls int
+		expectedBody  string
+		expectedError string
+	}{
+		{
+			description: "returns successful responses as is",
+			path:        "/repos/itsdalmo/test-repository/pulls/1/files",
+			maxRetries:  3,
+			maxWait:     time.Minute,
+			responses: []testResponse{
+				{status: http.StatusOK, body: "ok"},
+			},
+			expectedCalls: 1,
+			expectedBody:  "ok",
+		},
+		{
+			description: "retries server errors",
+			path:        "/repos/itsdalmo/test-repository/pulls/1/files",
+			maxRetries:  3,
+			maxWait:     time.Minute,
+			responses: []testResponse{
+				{status: http.StatusBadGateway},
+				{status: http.StatusServiceUnavailable},
+				{status: http.StatusOK, body: "ok"},
+			},
+			expectedCalls: 3,
+			expectedBody:  "ok",
+		},
+		{
+			description: "does not retry server errors for requests that change something",
+			method:      http.MethodPost,
+			path:        "/repos/itsdalmo/test-repository/issues/1/comments",
+			maxRetries:  3,
+			maxWait:     time.Minute,
+			responses: []testResponse{
+				{status: http.StatusBadGateway, body: "bad gateway"},
+			},
+			expectedCalls: 1,
+			expectedBody:  "bad gateway",
+		},
+		{
+			description: "retries server errors for graphql queries",
+			method:      http.MethodPost,
+			path:        "/graphql",
+			body:        `{"query":"query($owner:String!){repository(owner:$owner){id}}"}`,
+			maxRetries:  3,
+			maxWait:     time.Minute,
+			responses: []testResponse{
+				{status: http.StatusGatewayTimeout},
+				{status: http.StatusOK, body: `{"data":{}}`},
+			},
+			expectedCalls: 2,
+			expectedBody:  `{"data":{}}`,
+		},
+		{
+			description: "does not retry server errors for graphql mutations",
+			method:      http.MethodPost,
+			path:        "/graphql",
+			body:        `{"query":"mutation($input:EnqueuePullRequestInput!){enqueuePullRequest(input:$input){clientMutationId}}"}`,
+			maxRetries:  3,
+			maxWait:     time.Minute,
+			responses: []testResponse{
+				{status: http.StatusGatewayTimeout, body: "timeout"},
+			},
+			expectedCalls: 1,
+			expectedBody:  "timeout",
+		},
+		{
+			description: "honours retry-after",
+			method:      http.MethodPost,
+			path:        "/repos/itsdalmo/test-repository/pulls/1/files",
+			maxRetries:  3,
+			maxWait:     time.Minute,
+			responses: []testResponse{
+				{status: http.StatusForbidden, headers: map[string]string{"Retry-After": "0"}},
+				{status: http.StatusOK, body: "ok"},
+			},
+			expectedCalls: 2,
+			expectedBody:  "ok",
+		},
+		{
+			description: "honours x-ratelimit-reset",
+			path:        "/repos/itsdalmo/test-repository/pulls/1/files",
+			maxRetries:  3,
+			maxWait:     time.Minute,
+			responses: []testResponse{
+				{status: http.StatusForbidden, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset}},
+				{status: http.StatusOK, body: "ok"},
+			},
+			expectedCalls: 2,
+			expectedBody:  "ok",
+		},
+		{
+			description: "retries secondary rate limits",
+			path:        "/repos/itsdalmo/test-repository/pulls/1/files",
+			maxRetries:  3,
+			maxWait:     time.Minute,
+			responses: []testResponse{
+				{status: http.StatusForbidden, body: `{"message":"You have exceeded a secondary rate limit."}`},
+				{status: http.StatusOK, body: "ok"},
+			},
+			expectedCalls: 2,
+			expectedBody:  "ok",
+		},
+		{
+			description: "retries graphql rate limited errors",
+			method:      http.MethodPost,
+			path:        "/graphql",
+			maxRetries:  3,
+			maxWait:     time.Minute,
+			responses: []testResponse{
+				{status: http.StatusOK, body: `{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`},
+				{status: http.StatusOK, body: `{"data":{}}`},
+			},
+			expectedCalls: 2,
+			expectedBody:  `{"data":{}}`,
+		},
+		{
+			description: "does not retry other errors",
+			path:        "/repos/itsdalmo/test-repository/pulls/1/files",
+			maxRetries:  3,
+			maxWait:     time.Minute,
+			responses: []testResponse{
+				{status: http.StatusForbidden, body: `{"message":"Resource not accessible by integration"}`},
+			},
+			expectedCalls: 1,
+			expectedBody:  `{"message":"Resource not accessible by integration"}`,
+		},
+		{
+			description: "gives up after max retries",
+			path:        "/repos/itsdalmo/test-repository/pulls/1/files",
+			maxRetries:  2,
+			maxWait:     time.Minute,
+			responses: []testResponse{
+				{status: http.StatusBadGateway},
+				{status: http.StatusBadGateway},
+				{status: http.StatusBadGateway},
+			},
+			expectedCalls: 3,
+			expectedError: "giving up after 2 retries: server error (502 Bad Gateway)",
+		},
+		{
+			description: "gives up when the wait exceeds the budget",
+			path:        "/repos/itsdalmo/test-repository/pulls/1/files",
+			maxRetries:  3,
+			maxWait:     time.Minute,
+			responses: []testResponse{
+				{status: http.StatusForbidden, headers: map[string]string{"Retry-After": "3600"}},
+			},
+			expectedCalls: 1,
+			expectedError: "giving up after 0 retries: rate limited (403 Forbidden): next attempt in 1h0m0s would exceed the max retry wait of 1m0s",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			if tc.method == "" {
+				tc.method = http.MethodGet
+			}
+			if tc.body == "" {
+				tc.body = "request"
+			}
+
+			var calls int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tc.method, r.Method)
+				assert.Equal(t, tc.body, string(body))
+
+				response := tc.responses[calls]
+				calls++
+				for k, v := range response.headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(response.status)
+				fmt.Fprint(w, response.body)
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: &resource.RetryTransport{
+				MaxRetries: tc.maxRetries,
+				MaxWait:    tc.maxWait,
+				MinBackoff: time.Millisecond,
+			}}
+
+			req, err := http.NewRequest(tc.method, server.URL+tc.path, strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			if tc.expectedError != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.expectedError)
+				}
+			} else if assert.NoError(t, err) {
+				defer resp.Body.Close()
+				body, err := ioutil.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedBody, string(body))
+			}
+			assert.Equal(t, tc.expectedCalls, calls)
+		})
+	}
+}
+
+func TestGithubClientRetries(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `[{"filename":"README.md"}]`)
+	}))
+	defer server.Close()
+
+	source := resource.Source{
+		Repository:  "itsdalmo/test-repository",
+		AccessToken: "oauthtoken",
+		V3Endpoint:  server.URL + "/",
+		V4Endpoint:  server.URL + "/graphql",
+	}
+	require.NoError(t, source.Validate())
+
+	client, err := resource.NewGithubClient(&source, nil)
+	require.NoError(t, err)
+
+	files, err := client.ListModifiedFiles(1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"README.md"}, files)
+	}
+	assert.Equal(t, 2, calls)
+}